@@ -0,0 +1,77 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterMerakiSourceSpec defines the desired state of ClusterMerakiSource
+type ClusterMerakiSourceSpec struct {
+	MerakiSourceSpec `json:",inline"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// TargetNamespace is the namespace of the generated DNSEndpoint
+	TargetNamespace string `json:"targetNamespace"`
+
+	// APIKeySecretRef selects the Meraki API key from a Secret in the
+	// controller namespace. Defaults to the controller API key
+	// +optional
+	APIKeySecretRef *corev1.SecretKeySelector `json:"apiKeySecretRef,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+
+// ClusterMerakiSource is the Schema for the clustermerakisources API
+type ClusterMerakiSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterMerakiSourceSpec `json:"spec,omitempty"`
+	Status MerakiSourceStatus      `json:"status,omitempty"`
+}
+
+// GetSourceSpec returns the source spec
+func (in *ClusterMerakiSource) GetSourceSpec() *MerakiSourceSpec {
+	return &in.Spec.MerakiSourceSpec
+}
+
+// GetSourceStatus returns the source status
+func (in *ClusterMerakiSource) GetSourceStatus() *MerakiSourceStatus {
+	return &in.Status
+}
+
+// GetTargetNamespace returns the namespace of the generated DNSEndpoint
+func (in *ClusterMerakiSource) GetTargetNamespace() string {
+	return in.Spec.TargetNamespace
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterMerakiSourceList contains a list of ClusterMerakiSource
+type ClusterMerakiSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterMerakiSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterMerakiSource{}, &ClusterMerakiSourceList{})
+}
@@ -0,0 +1,110 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MerakiConnectionSpec defines the desired state of MerakiConnection
+type MerakiConnectionSpec struct {
+	// Organization is a reference to the organization (name or id)
+	Organization MerakiRef `json:"organization,omitempty"`
+
+	// APIKeySecretRef selects the Meraki API key from a Secret in the namespace
+	// of the connection
+	APIKeySecretRef corev1.SecretKeySelector `json:"apiKeySecretRef"`
+
+	// Region is the Meraki dashboard region of the organization. Defaults to
+	// the controller --region
+	// +optional
+	Region Region `json:"region,omitempty"`
+
+	// BaseURL is the base URL of the Meraki dashboard API. Overrides the
+	// region
+	// +optional
+	BaseURL string `json:"baseURL,omitempty"`
+
+	// Proxy is the URL of an HTTP proxy used for Meraki API requests. Defaults
+	// to the proxy configured in the controller environment
+	// +optional
+	Proxy string `json:"proxy,omitempty"`
+
+	// ProxyCredentialsSecretRef selects proxy credentials in the form
+	// username:password from a Secret in the namespace of the connection
+	// +optional
+	ProxyCredentialsSecretRef *corev1.SecretKeySelector `json:"proxyCredentialsSecretRef,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+
+	// RateLimit is the maximum number of Meraki API requests per second made by
+	// all sources using the connection
+	// +optional
+	RateLimit *int32 `json:"rateLimit,omitempty"`
+}
+
+// MerakiConnectionStatus defines the observed state of MerakiConnection
+type MerakiConnectionStatus struct {
+	// Organization is the organization found with the connection
+	// +optional
+	Organization MerakiRef `json:"organization,omitempty"`
+
+	// CheckedAt is the time the connection was last checked against the
+	// Meraki API
+	// +optional
+	CheckedAt *metav1.Time `json:"checkedAt,omitempty"`
+
+	// Conditions describe the current state of the connection
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// GetCondition returns the condition with the given type, if any
+func (s *MerakiConnectionStatus) GetCondition(conditionType ConditionType) *Condition {
+	return getCondition(s.Conditions, conditionType)
+}
+
+// SetCondition adds or updates the condition with the given type. The
+// transition time is only updated when the status changes
+func (s *MerakiConnectionStatus) SetCondition(conditionType ConditionType, status corev1.ConditionStatus, reason, message string) {
+	s.Conditions = setCondition(s.Conditions, conditionType, status, reason, message)
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// MerakiConnection is the Schema for the merakiconnections API
+type MerakiConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MerakiConnectionSpec   `json:"spec,omitempty"`
+	Status MerakiConnectionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MerakiConnectionList contains a list of MerakiConnection
+type MerakiConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MerakiConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MerakiConnection{}, &MerakiConnectionList{})
+}
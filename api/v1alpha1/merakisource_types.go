@@ -28,6 +28,21 @@ var (
 	}
 )
 
+const (
+	// AllowDeletionAnnotation acknowledges a sync that removes more records
+	// than allowed by MaxDeletionPercent or MinEndpoints. It is removed once
+	// the sync has been applied
+	AllowDeletionAnnotation = "dns.jossware.com/allow-deletion"
+
+	// SyncRequestedAnnotation requests a sync from Meraki that bypasses the API
+	// throttle. Any new value, typically a timestamp, triggers a single sync
+	SyncRequestedAnnotation = "dns.jossware.com/sync-requested-at"
+
+	// Finalizer is added to sources so the deletion policy can be applied
+	// before they are removed
+	Finalizer = "dns.jossware.com/finalizer"
+)
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
 // MerakiRef is a reference to a Meraki resource
@@ -38,12 +53,38 @@ type MerakiRef struct {
 
 // MerakiSourceSpec defines the desired state of MerakiSource
 type MerakiSourceSpec struct {
-	// Organization is a reference to the organization to query (name or id)
+	// ConnectionRef references a MerakiConnection with the API credentials and
+	// settings to use. ClusterMerakiSources reference connections in the
+	// controller namespace. Defaults to the controller API key
+	// +optional
+	ConnectionRef *corev1.LocalObjectReference `json:"connectionRef,omitempty"`
+
+	// Region is the Meraki dashboard region of the organization. Ignored when
+	// ConnectionRef is set. Defaults to the controller --region
+	// +optional
+	Region Region `json:"region,omitempty"`
+
+	// Organization is a reference to the organization to query (name or id).
+	// Defaults to the organization of the connection
 	Organization MerakiRef `json:"organization,omitempty"`
 
 	// Network is a reference to the network to query (name or id)
 	Network MerakiRef `json:"network,omitempty"`
 
+	// Mode selects what the source publishes records for. Defaults to Clients
+	// +optional
+	Mode SourceMode `json:"mode,omitempty"`
+
+	// Uplinks configures the records published in Uplinks mode
+	// +optional
+	Uplinks *Uplinks `json:"uplinks,omitempty"`
+
+	// DashboardConfig reads the domain, TTL and address filters from dns-
+	// tags and notes on the Meraki network, so network admins can manage them
+	// in the Meraki dashboard
+	// +optional
+	DashboardConfig *DashboardConfig `json:"dashboardConfig,omitempty"`
+
 	// Domain is the DNS suffix to use for the client DNS registration
 	Domain string `json:"domain,omitempty"`
 
@@ -53,6 +94,536 @@ type MerakiSourceSpec struct {
 	// used will depend on the provider
 	// https://github.com/kubernetes-sigs/external-dns/blob/master/docs/ttl.md
 	TTL *int64 `json:"ttl,omitempty"`
+
+	// Subdomains places clients in subdomains of Domain based on their VLAN or
+	// SSID
+	// +optional
+	Subdomains *Subdomains `json:"subdomains,omitempty"`
+
+	// NameSources is the order in which the name of a client is picked. The
+	// first source with a name for the client is used. The MAC address is used
+	// if none has one. Defaults to description, mac
+	// +optional
+	NameSources []NameSource `json:"nameSources,omitempty"`
+
+	// Overrides adjust or suppress the records published for individual
+	// clients
+	// +optional
+	Overrides []ClientOverride `json:"overrides,omitempty"`
+
+	// AllowedCIDRs limit the client addresses that are published. Once set,
+	// addresses of an IP family without an allowed CIDR are not published.
+	// Defaults to all addresses
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// ExcludedCIDRs are client address ranges that are never published, e.g.
+	// a guest NAT range
+	// +optional
+	ExcludedCIDRs []string `json:"excludedCIDRs,omitempty"`
+
+	// Filter limits the clients that are published
+	// +optional
+	Filter *ClientFilter `json:"filter,omitempty"`
+
+	// AddressTranslation translates the addresses of A records, e.g. for
+	// subnets that are reached through Meraki VPN subnet translation
+	// +optional
+	AddressTranslation *AddressTranslation `json:"addressTranslation,omitempty"`
+
+	// ServiceDiscovery advertises services of matching clients with DNS-SD
+	// records so they can be browsed with unicast DNS
+	// +optional
+	ServiceDiscovery []ServiceDiscoveryRule `json:"serviceDiscovery,omitempty"`
+
+	// Groups publish names that resolve to every matching client, e.g.
+	// printers.example.com
+	// +optional
+	Groups []Group `json:"groups,omitempty"`
+
+	// MetadataRecords publishes a TXT record describing each client
+	// +optional
+	MetadataRecords *MetadataRecords `json:"metadataRecords,omitempty"`
+
+	// PublicView publishes the public IPs of clients exposed with MX 1:1 NAT
+	// rules to a separate domain and DNSEndpoint
+	// +optional
+	PublicView *PublicView `json:"publicView,omitempty"`
+
+	// PortForwarding publishes SRV records for the services exposed with MX
+	// port forwarding rules
+	// +optional
+	PortForwarding *PortForwarding `json:"portForwarding,omitempty"`
+
+	// ProviderSpecific properties are set on every record. Values may be Go
+	// templates that are rendered with the Meraki client or uplink of the
+	// record. Templated values are left out of group, DNS-SD pointer and port
+	// forwarding records, which are not about a single client
+	// +optional
+	ProviderSpecific []ProviderSpecificProperty `json:"providerSpecific,omitempty"`
+
+	// EndpointLabels are set on every record. Values may be Go templates that
+	// are rendered with the Meraki client or uplink of the record. Templated
+	// values are left out of group, DNS-SD pointer and port forwarding
+	// records, which are not about a single client
+	// +optional
+	EndpointLabels map[string]string `json:"endpointLabels,omitempty"`
+
+	// DryRun computes the records from Meraki and reports them in the status
+	// without writing the DNSEndpoint
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Suspend stops syncing from Meraki. The existing DNSEndpoint is left
+	// untouched until the source is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+
+	// MaxDeletionPercent is the largest percentage of the current records that
+	// a single sync may remove without the allow-deletion annotation
+	// +optional
+	MaxDeletionPercent *int32 `json:"maxDeletionPercent,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// MinEndpoints is the smallest number of records a sync may shrink the
+	// DNSEndpoint to without the allow-deletion annotation
+	// +optional
+	MinEndpoints *int32 `json:"minEndpoints,omitempty"`
+
+	// Interval is how often the source is synced from Meraki. Defaults to the
+	// controller --requeue-interval
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// MinSyncInterval is the minimum time between Meraki API queries for the
+	// source. Defaults to the controller --throttle-interval
+	// +optional
+	MinSyncInterval *metav1.Duration `json:"minSyncInterval,omitempty"`
+
+	// DeletionPolicy controls what happens to the DNSEndpoints of the source
+	// when it is deleted. Defaults to Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Override;Default
+
+// DashboardPrecedence decides whether settings from the Meraki dashboard
+// override the spec or only default unset fields
+type DashboardPrecedence string
+
+const (
+	DashboardPrecedenceOverride DashboardPrecedence = "Override"
+	DashboardPrecedenceDefault  DashboardPrecedence = "Default"
+)
+
+// DashboardConfig reads source settings from the tags and notes of the Meraki
+// network. Settings are key=value pairs: dns-domain, dns-ttl,
+// dns-allowed-cidrs and dns-excluded-cidrs, with comma separated CIDRs. Tags
+// are read first, so a setting in the notes wins over a tag
+type DashboardConfig struct {
+	// Precedence of the dashboard settings. Override replaces the spec fields,
+	// Default only sets fields that are empty in the spec. Defaults to
+	// Override
+	// +optional
+	Precedence DashboardPrecedence `json:"precedence,omitempty"`
+}
+
+// Group publishes a record with the addresses of every client matching the
+// selector. IPv4 addresses are published as an A record and IPv6 addresses as
+// an AAAA record
+type Group struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Name of the group record, relative to the source domain
+	Name string `json:"name"`
+
+	// Selector selects the members of the group
+	Selector ClientSelector `json:"selector"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// TTL of the group record. Defaults to the source TTL
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+
+	// ExcludeMembers publishes only the group record for the members instead
+	// of their own records as well
+	// +optional
+	ExcludeMembers bool `json:"excludeMembers,omitempty"`
+}
+
+// ClientFilter limits the clients of a source
+type ClientFilter struct {
+	// GroupPolicies filters clients by the Meraki policy applied to them
+	// +optional
+	GroupPolicies *GroupPolicyFilter `json:"groupPolicies,omitempty"`
+}
+
+// GroupPolicyFilter filters clients by policy. A policy is the name of a group
+// policy, or Normal, Whitelisted or Blocked for clients without one. Names are
+// compared case-insensitively
+type GroupPolicyFilter struct {
+	// Include publishes only clients with one of these policies. Defaults to
+	// all policies
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude never publishes clients with one of these policies
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// AddressTranslation translates record addresses with CIDR to CIDR mappings.
+// By default the targets of the source records are translated in place. With
+// a Domain, or a separate endpoint, translated copies are published alongside
+// the local records for per-site views
+type AddressTranslation struct {
+	// Mappings translate addresses in one CIDR to the same host address in
+	// another CIDR of the same size
+	Mappings []AddressMapping `json:"mappings"`
+
+	// Domain publishes translated copies of the A records in this domain, in
+	// addition to the local records in the source domain
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// SeparateEndpoint publishes the translated records to their own
+	// DNSEndpoint, named after the source with a -translated suffix
+	// +optional
+	SeparateEndpoint bool `json:"separateEndpoint,omitempty"`
+
+	// Labels are set on the separate DNSEndpoint
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// AddressMapping maps the addresses in one CIDR to another, e.g.
+// 192.168.128.0/24 to 10.50.128.0/24 translates 192.168.128.5 to 10.50.128.5
+type AddressMapping struct {
+	// From is the local CIDR
+	From string `json:"from"`
+
+	// To is the translated CIDR. It must be the same size as From
+	To string `json:"to"`
+}
+
+// +kubebuilder:validation:Enum=tcp;udp
+
+// ServiceProtocol is the transport protocol of a DNS-SD service
+type ServiceProtocol string
+
+const (
+	ServiceProtocolTCP ServiceProtocol = "tcp"
+	ServiceProtocolUDP ServiceProtocol = "udp"
+)
+
+// ServiceDiscoveryRule advertises a DNS-SD service for every client matching
+// the selector, following RFC 6763
+type ServiceDiscoveryRule struct {
+	// Selector selects the clients that provide the service
+	Selector ClientSelector `json:"selector"`
+
+	// +kubebuilder:validation:MinLength=1
+
+	// Service is the DNS-SD service type without the leading underscore, e.g.
+	// ipp or airplay
+	Service string `json:"service"`
+
+	// Protocol of the service. Defaults to tcp
+	// +optional
+	Protocol ServiceProtocol `json:"protocol,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+
+	// Port the service listens on
+	Port int32 `json:"port"`
+
+	// TXT are the key=value pairs of the service TXT record
+	// +optional
+	TXT []string `json:"txt,omitempty"`
+}
+
+// ClientSelector selects Meraki clients. A client must match every field
+// that is set, and any of the values of a list. An empty selector matches
+// every client
+type ClientSelector struct {
+	// Manufacturers match the client manufacturer, compared case-insensitively
+	// +optional
+	Manufacturers []string `json:"manufacturers,omitempty"`
+
+	// OperatingSystems match clients whose OS contains one of the values,
+	// compared case-insensitively, e.g. iOS
+	// +optional
+	OperatingSystems []string `json:"operatingSystems,omitempty"`
+
+	// VLANs match the client VLAN
+	// +optional
+	VLANs []int32 `json:"vlans,omitempty"`
+
+	// DescriptionRegex is a regular expression matched against the client
+	// description
+	// +optional
+	DescriptionRegex string `json:"descriptionRegex,omitempty"`
+
+	// MACs match the client MAC address, compared case-insensitively
+	// +optional
+	MACs []string `json:"macs,omitempty"`
+}
+
+// PortForwarding publishes an SRV record for each MX port forwarding rule,
+// e.g. _https._tcp.example.com, pointing at the client with the LAN IP of the
+// rule and the local port
+type PortForwarding struct {
+	// Services maps port forwarding rule names to service labels. Rules that
+	// are not mapped use their name as the service label
+	// +optional
+	Services []ServiceMapping `json:"services,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// TTL of the SRV records. Defaults to the source TTL
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+}
+
+// ServiceMapping sets the service label for a port forwarding rule
+type ServiceMapping struct {
+	// Rule is the name of the port forwarding rule, compared case-insensitively
+	Rule string `json:"rule"`
+
+	// Service is the service label, without the leading underscore, e.g.
+	// https
+	Service string `json:"service"`
+}
+
+// PublicView publishes the public IPs of clients exposed with MX 1:1 NAT
+// rules. Each client with a NAT rule gets a record with the same name in the
+// public domain pointing at the public IP of the rule
+type PublicView struct {
+	// +kubebuilder:validation:MinLength=1
+
+	// Domain is the public DNS suffix
+	Domain string `json:"domain"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// TTL of the public records. Defaults to the source TTL
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+
+	// Labels are set on the public DNSEndpoint, e.g. to select it with the
+	// --label-filter of the external-dns instance for the public zone
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=sm;description;user;mac
+
+// NameSource is where the name of a client record comes from
+type NameSource string
+
+const (
+	// NameSourceSM is the name of the Systems Manager device with the MAC
+	// address of the client
+	NameSourceSM NameSource = "sm"
+
+	// NameSourceDescription is the client description, usually the DHCP
+	// hostname
+	NameSourceDescription NameSource = "description"
+
+	// NameSourceUser is the user of the client, without any email domain
+	NameSourceUser NameSource = "user"
+
+	// NameSourceMAC is the MAC address of the client, e.g. 00-11-22-33-44-55
+	NameSourceMAC NameSource = "mac"
+)
+
+// +kubebuilder:validation:Enum=Clients;Uplinks
+
+// SourceMode selects what a source publishes records for
+type SourceMode string
+
+const (
+	// SourceModeClients publishes a record for each network client
+	SourceModeClients SourceMode = "Clients"
+
+	// SourceModeUplinks publishes a record with the public IP of each security
+	// appliance uplink, e.g. branch1-wan1.example.com
+	SourceModeUplinks SourceMode = "Uplinks"
+)
+
+// DefaultUplinkTTL is the TTL of uplink records. It is short so records
+// follow changing public IPs quickly
+const DefaultUplinkTTL int64 = 60
+
+// Uplinks configures the records published for appliance uplinks
+type Uplinks struct {
+	// ActiveOnly only publishes uplinks that are carrying traffic
+	// +optional
+	ActiveOnly bool `json:"activeOnly,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// TTL of the uplink records. Defaults to 60
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=global;china;india;canada;fedramp
+
+// Region is a Meraki dashboard region. Each region is served by its own API
+// host
+type Region string
+
+// Regions of the Meraki dashboard
+const (
+	RegionGlobal  Region = "global"
+	RegionChina   Region = "china"
+	RegionIndia   Region = "india"
+	RegionCanada  Region = "canada"
+	RegionFedRAMP Region = "fedramp"
+)
+
+// +kubebuilder:validation:Enum=Delete;Orphan
+
+// DeletionPolicy controls what happens to the DNSEndpoints of a deleted source
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the DNSEndpoints, removing the records from DNS
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyOrphan keeps the DNSEndpoints, and the records in DNS, but
+	// removes the reference to the source
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// ProviderSpecificProperty is a provider specific setting that is passed
+// through to external-dns, e.g. external-dns.alpha.kubernetes.io/cloudflare-proxied
+type ProviderSpecificProperty struct {
+	// Name of the property
+	Name string `json:"name"`
+
+	// Value of the property. It may be a Go template that is rendered with the
+	// Meraki client, e.g. "{{ .Vlan }}"
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// Subdomains maps client network segments to subdomain labels
+type Subdomains struct {
+	// Default is the label used for clients that do not match any rule. If it
+	// is empty, unmatched clients are registered directly under Domain
+	// +optional
+	Default string `json:"default,omitempty"`
+
+	// Rules are evaluated in order and the first matching rule is used
+	// +optional
+	Rules []SubdomainRule `json:"rules,omitempty"`
+}
+
+// SubdomainRule maps a VLAN ID or SSID to a subdomain label
+type SubdomainRule struct {
+	// VLAN matches clients on this VLAN ID
+	// +optional
+	VLAN *int32 `json:"vlan,omitempty"`
+
+	// SSID matches wireless clients connected to this SSID
+	// +optional
+	SSID string `json:"ssid,omitempty"`
+
+	// Label is the subdomain inserted between the client name and Domain
+	Label string `json:"label"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// TTL overrides the source TTL for clients matching this rule
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+}
+
+// ClientOverride adjusts the records published for a single client. The client
+// is matched by MAC address or Meraki client ID
+type ClientOverride struct {
+	// MAC matches the client with this MAC address
+	// +optional
+	MAC string `json:"mac,omitempty"`
+
+	// ClientID matches the client with this Meraki client ID
+	// +optional
+	ClientID string `json:"clientId,omitempty"`
+
+	// Name replaces the name derived from the client description or MAC
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Aliases are additional names for the client in the same domain
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
+
+	// +kubebuilder:validation:Enum=CNAME;A
+
+	// AliasType is the record type used for aliases. CNAME records point to the
+	// client record and A records point to the client IP. Defaults to CNAME
+	// +optional
+	AliasType string `json:"aliasType,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// TTL overrides the TTL of the records for the client
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+
+	// Labels are added to the records for the client. Values may be Go
+	// templates that are rendered with the Meraki client
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ProviderSpecific properties are added to the records for the client,
+	// replacing source properties with the same name
+	// +optional
+	ProviderSpecific []ProviderSpecificProperty `json:"providerSpecific,omitempty"`
+
+	// Exclude suppresses all records for the client
+	// +optional
+	Exclude bool `json:"exclude,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=mac;manufacturer;os;vlan;deviceName;lastSeen
+
+// MetadataField is a client field that can be included in a metadata record
+type MetadataField string
+
+const (
+	MetadataFieldMAC          MetadataField = "mac"
+	MetadataFieldManufacturer MetadataField = "manufacturer"
+	MetadataFieldOS           MetadataField = "os"
+	MetadataFieldVLAN         MetadataField = "vlan"
+	MetadataFieldDeviceName   MetadataField = "deviceName"
+	MetadataFieldLastSeen     MetadataField = "lastSeen"
+)
+
+// DefaultMetadataPrefix is the metadata record prefix used when none is set
+const DefaultMetadataPrefix = "_meraki."
+
+// MetadataRecords configures TXT records describing each client
+type MetadataRecords struct {
+	// Prefix is prepended to the client record name to build the name of the
+	// TXT record. It must not be the same as the external-dns TXT registry
+	// prefix. Defaults to "_meraki."
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Fields are the client fields included in the record. Defaults to all
+	// fields except lastSeen, which changes on every sync
+	// +optional
+	Fields []MetadataField `json:"fields,omitempty"`
 }
 
 // MerakiSourceStatus defines the observed state of MerakiSource
@@ -61,9 +632,195 @@ type MerakiSourceStatus struct {
 	// +optional
 	Endpoint corev1.ObjectReference `json:"endpoint,omitempty"`
 
+	// PublicEndpoint is a reference to the DNSEndpoint with the public view
+	// records
+	// +optional
+	PublicEndpoint *corev1.ObjectReference `json:"publicEndpoint,omitempty"`
+
+	// TranslatedEndpoint is a reference to the DNSEndpoint with the translated
+	// records
+	// +optional
+	TranslatedEndpoint *corev1.ObjectReference `json:"translatedEndpoint,omitempty"`
+
 	// SyncedAt is the time the endpoint was last synced from Meraki
 	// +optional
 	SyncedAt *metav1.Time `json:"syncedAt,omitempty"`
+
+	// LastHandledSyncRequest is the value of the sync-requested-at annotation
+	// that was last handled
+	// +optional
+	LastHandledSyncRequest string `json:"lastHandledSyncRequest,omitempty"`
+
+	// DryRun describes the records that would have been published by the last
+	// dry run sync
+	// +optional
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+
+	// DryRunViews describe the records that would have been published to the
+	// DNSEndpoints of the public and translated views by the last dry run sync
+	// +optional
+	DryRunViews []DryRunStatus `json:"dryRunViews,omitempty"`
+
+	// Skipped describes the clients that were not published because they have
+	// no usable address
+	// +optional
+	Skipped *SkippedStatus `json:"skipped,omitempty"`
+
+	// EffectiveConfig is the configuration the records were rendered with when
+	// settings are read from the Meraki dashboard
+	// +optional
+	EffectiveConfig *EffectiveConfig `json:"effectiveConfig,omitempty"`
+
+	// Conditions describe the current state of the source
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ConditionType is the type of a condition
+type ConditionType string
+
+const (
+	// ConditionSuspended is true when syncing has been suspended
+	ConditionSuspended ConditionType = "Suspended"
+
+	// ConditionDeletionBlocked is true when a sync was not applied because it
+	// would remove too many records
+	ConditionDeletionBlocked ConditionType = "DeletionBlocked"
+
+	// ConditionMetadataRecordsBlocked is true when metadata records are not
+	// published because the controller TXT prefix is missing or collides with
+	// the metadata record prefix
+	ConditionMetadataRecordsBlocked ConditionType = "MetadataRecordsBlocked"
+
+	// ConditionReady is true when a MerakiConnection has been validated
+	// against the Meraki API
+	ConditionReady ConditionType = "Ready"
+)
+
+// Condition describes the state of a resource
+type Condition struct {
+	// Type of the condition
+	Type ConditionType `json:"type"`
+
+	// Status of the condition, one of True, False or Unknown
+	Status corev1.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the last time the condition changed status
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable explanation for the condition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GetCondition returns the condition with the given type, if any
+func (s *MerakiSourceStatus) GetCondition(conditionType ConditionType) *Condition {
+	return getCondition(s.Conditions, conditionType)
+}
+
+// SetCondition adds or updates the condition with the given type. The
+// transition time is only updated when the status changes
+func (s *MerakiSourceStatus) SetCondition(conditionType ConditionType, status corev1.ConditionStatus, reason, message string) {
+	s.Conditions = setCondition(s.Conditions, conditionType, status, reason, message)
+}
+
+func getCondition(conditions []Condition, conditionType ConditionType) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func setCondition(conditions []Condition, conditionType ConditionType, status corev1.ConditionStatus, reason, message string) []Condition {
+	c := getCondition(conditions, conditionType)
+	if c == nil {
+		conditions = append(conditions, Condition{Type: conditionType})
+		c = &conditions[len(conditions)-1]
+	}
+	if c.Status != status {
+		c.Status = status
+		c.LastTransitionTime = metav1.Now()
+	}
+	c.Reason = reason
+	c.Message = message
+	return conditions
+}
+
+// SkippedStatus describes clients that were not published because their
+// address is missing, invalid, link-local or outside the allowed CIDRs
+type SkippedStatus struct {
+	// Clients is the number of clients that were skipped because they have
+	// no usable address
+	Clients int `json:"clients"`
+
+	// Addresses is the number of addresses that were left out, including the
+	// addresses of clients that are published with their other address
+	Addresses int `json:"addresses"`
+
+	// Sample is a sample of the left out addresses and the reason, e.g.
+	// "00:11:22:33:44:55: no IP address"
+	// +optional
+	Sample []string `json:"sample,omitempty"`
+}
+
+// EffectiveConfig is the configuration of a source after applying the settings
+// from the Meraki dashboard
+type EffectiveConfig struct {
+	// Domain is the DNS suffix of the records
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// TTL of the records
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+
+	// AllowedCIDRs limit the client addresses that are published
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// ExcludedCIDRs are client address ranges that are never published
+	// +optional
+	ExcludedCIDRs []string `json:"excludedCIDRs,omitempty"`
+
+	// FromDashboard lists the settings that were taken from the dashboard,
+	// e.g. dns-domain
+	// +optional
+	FromDashboard []string `json:"fromDashboard,omitempty"`
+}
+
+// DryRunStatus describes the records computed by a dry run sync and how they
+// differ from the current DNSEndpoint
+type DryRunStatus struct {
+	// Name is the name of the DNSEndpoint the records would be written to
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Endpoints is the number of records that would be published
+	Endpoints int `json:"endpoints"`
+
+	// Added is the number of records that would be added
+	Added int `json:"added"`
+
+	// Removed is the number of records that would be removed
+	Removed int `json:"removed"`
+
+	// Changed is the number of records that would be changed
+	Changed int `json:"changed"`
+
+	// Sample is a sample of the records that would be published
+	// +optional
+	Sample []string `json:"sample,omitempty"`
+
+	// Diff is a sample of the differences from the current DNSEndpoint
+	// +optional
+	Diff []string `json:"diff,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -78,6 +835,21 @@ type MerakiSource struct {
 	Status MerakiSourceStatus `json:"status,omitempty"`
 }
 
+// GetSourceSpec returns the source spec
+func (in *MerakiSource) GetSourceSpec() *MerakiSourceSpec {
+	return &in.Spec
+}
+
+// GetSourceStatus returns the source status
+func (in *MerakiSource) GetSourceStatus() *MerakiSourceStatus {
+	return &in.Status
+}
+
+// GetTargetNamespace returns the namespace of the generated DNSEndpoint
+func (in *MerakiSource) GetTargetNamespace() string {
+	return in.Namespace
+}
+
 // +kubebuilder:object:root=true
 
 // MerakiSourceList contains a list of MerakiSource
@@ -20,9 +20,475 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressMapping) DeepCopyInto(out *AddressMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressMapping.
+func (in *AddressMapping) DeepCopy() *AddressMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressTranslation) DeepCopyInto(out *AddressTranslation) {
+	*out = *in
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make([]AddressMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressTranslation.
+func (in *AddressTranslation) DeepCopy() *AddressTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientFilter) DeepCopyInto(out *ClientFilter) {
+	*out = *in
+	if in.GroupPolicies != nil {
+		in, out := &in.GroupPolicies, &out.GroupPolicies
+		*out = new(GroupPolicyFilter)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientFilter.
+func (in *ClientFilter) DeepCopy() *ClientFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientOverride) DeepCopyInto(out *ClientOverride) {
+	*out = *in
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProviderSpecific != nil {
+		in, out := &in.ProviderSpecific, &out.ProviderSpecific
+		*out = make([]ProviderSpecificProperty, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientOverride.
+func (in *ClientOverride) DeepCopy() *ClientOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSelector) DeepCopyInto(out *ClientSelector) {
+	*out = *in
+	if in.Manufacturers != nil {
+		in, out := &in.Manufacturers, &out.Manufacturers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OperatingSystems != nil {
+		in, out := &in.OperatingSystems, &out.OperatingSystems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VLANs != nil {
+		in, out := &in.VLANs, &out.VLANs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.MACs != nil {
+		in, out := &in.MACs, &out.MACs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSelector.
+func (in *ClientSelector) DeepCopy() *ClientSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMerakiSource) DeepCopyInto(out *ClusterMerakiSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMerakiSource.
+func (in *ClusterMerakiSource) DeepCopy() *ClusterMerakiSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMerakiSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMerakiSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMerakiSourceList) DeepCopyInto(out *ClusterMerakiSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterMerakiSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMerakiSourceList.
+func (in *ClusterMerakiSourceList) DeepCopy() *ClusterMerakiSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMerakiSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMerakiSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMerakiSourceSpec) DeepCopyInto(out *ClusterMerakiSourceSpec) {
+	*out = *in
+	in.MerakiSourceSpec.DeepCopyInto(&out.MerakiSourceSpec)
+	if in.APIKeySecretRef != nil {
+		in, out := &in.APIKeySecretRef, &out.APIKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMerakiSourceSpec.
+func (in *ClusterMerakiSourceSpec) DeepCopy() *ClusterMerakiSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMerakiSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardConfig) DeepCopyInto(out *DashboardConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardConfig.
+func (in *DashboardConfig) DeepCopy() *DashboardConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	if in.Sample != nil {
+		in, out := &in.Sample, &out.Sample
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveConfig) DeepCopyInto(out *EffectiveConfig) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedCIDRs != nil {
+		in, out := &in.ExcludedCIDRs, &out.ExcludedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FromDashboard != nil {
+		in, out := &in.FromDashboard, &out.FromDashboard
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveConfig.
+func (in *EffectiveConfig) DeepCopy() *EffectiveConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Group) DeepCopyInto(out *Group) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Group.
+func (in *Group) DeepCopy() *Group {
+	if in == nil {
+		return nil
+	}
+	out := new(Group)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupPolicyFilter) DeepCopyInto(out *GroupPolicyFilter) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupPolicyFilter.
+func (in *GroupPolicyFilter) DeepCopy() *GroupPolicyFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupPolicyFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MerakiConnection) DeepCopyInto(out *MerakiConnection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MerakiConnection.
+func (in *MerakiConnection) DeepCopy() *MerakiConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(MerakiConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MerakiConnection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MerakiConnectionList) DeepCopyInto(out *MerakiConnectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MerakiConnection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MerakiConnectionList.
+func (in *MerakiConnectionList) DeepCopy() *MerakiConnectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(MerakiConnectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MerakiConnectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MerakiConnectionSpec) DeepCopyInto(out *MerakiConnectionSpec) {
+	*out = *in
+	out.Organization = in.Organization
+	in.APIKeySecretRef.DeepCopyInto(&out.APIKeySecretRef)
+	if in.ProxyCredentialsSecretRef != nil {
+		in, out := &in.ProxyCredentialsSecretRef, &out.ProxyCredentialsSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MerakiConnectionSpec.
+func (in *MerakiConnectionSpec) DeepCopy() *MerakiConnectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MerakiConnectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MerakiConnectionStatus) DeepCopyInto(out *MerakiConnectionStatus) {
+	*out = *in
+	out.Organization = in.Organization
+	if in.CheckedAt != nil {
+		in, out := &in.CheckedAt, &out.CheckedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MerakiConnectionStatus.
+func (in *MerakiConnectionStatus) DeepCopy() *MerakiConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MerakiConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MerakiRef) DeepCopyInto(out *MerakiRef) {
 	*out = *in
@@ -100,13 +566,126 @@ func (in *MerakiSourceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MerakiSourceSpec) DeepCopyInto(out *MerakiSourceSpec) {
 	*out = *in
+	if in.ConnectionRef != nil {
+		in, out := &in.ConnectionRef, &out.ConnectionRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	out.Organization = in.Organization
 	out.Network = in.Network
+	if in.Uplinks != nil {
+		in, out := &in.Uplinks, &out.Uplinks
+		*out = new(Uplinks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DashboardConfig != nil {
+		in, out := &in.DashboardConfig, &out.DashboardConfig
+		*out = new(DashboardConfig)
+		**out = **in
+	}
 	if in.TTL != nil {
 		in, out := &in.TTL, &out.TTL
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Subdomains != nil {
+		in, out := &in.Subdomains, &out.Subdomains
+		*out = new(Subdomains)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NameSources != nil {
+		in, out := &in.NameSources, &out.NameSources
+		*out = make([]NameSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]ClientOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedCIDRs != nil {
+		in, out := &in.ExcludedCIDRs, &out.ExcludedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(ClientFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddressTranslation != nil {
+		in, out := &in.AddressTranslation, &out.AddressTranslation
+		*out = new(AddressTranslation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceDiscovery != nil {
+		in, out := &in.ServiceDiscovery, &out.ServiceDiscovery
+		*out = make([]ServiceDiscoveryRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]Group, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetadataRecords != nil {
+		in, out := &in.MetadataRecords, &out.MetadataRecords
+		*out = new(MetadataRecords)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PublicView != nil {
+		in, out := &in.PublicView, &out.PublicView
+		*out = new(PublicView)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PortForwarding != nil {
+		in, out := &in.PortForwarding, &out.PortForwarding
+		*out = new(PortForwarding)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderSpecific != nil {
+		in, out := &in.ProviderSpecific, &out.ProviderSpecific
+		*out = make([]ProviderSpecificProperty, len(*in))
+		copy(*out, *in)
+	}
+	if in.EndpointLabels != nil {
+		in, out := &in.EndpointLabels, &out.EndpointLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxDeletionPercent != nil {
+		in, out := &in.MaxDeletionPercent, &out.MaxDeletionPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinEndpoints != nil {
+		in, out := &in.MinEndpoints, &out.MinEndpoints
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinSyncInterval != nil {
+		in, out := &in.MinSyncInterval, &out.MinSyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MerakiSourceSpec.
@@ -123,10 +702,49 @@ func (in *MerakiSourceSpec) DeepCopy() *MerakiSourceSpec {
 func (in *MerakiSourceStatus) DeepCopyInto(out *MerakiSourceStatus) {
 	*out = *in
 	out.Endpoint = in.Endpoint
+	if in.PublicEndpoint != nil {
+		in, out := &in.PublicEndpoint, &out.PublicEndpoint
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.TranslatedEndpoint != nil {
+		in, out := &in.TranslatedEndpoint, &out.TranslatedEndpoint
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
 	if in.SyncedAt != nil {
 		in, out := &in.SyncedAt, &out.SyncedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRunViews != nil {
+		in, out := &in.DryRunViews, &out.DryRunViews
+		*out = make([]DryRunStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Skipped != nil {
+		in, out := &in.Skipped, &out.Skipped
+		*out = new(SkippedStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(EffectiveConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MerakiSourceStatus.
@@ -138,3 +756,213 @@ func (in *MerakiSourceStatus) DeepCopy() *MerakiSourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataRecords) DeepCopyInto(out *MetadataRecords) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]MetadataField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataRecords.
+func (in *MetadataRecords) DeepCopy() *MetadataRecords {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataRecords)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortForwarding) DeepCopyInto(out *PortForwarding) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]ServiceMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortForwarding.
+func (in *PortForwarding) DeepCopy() *PortForwarding {
+	if in == nil {
+		return nil
+	}
+	out := new(PortForwarding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderSpecificProperty) DeepCopyInto(out *ProviderSpecificProperty) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpecificProperty.
+func (in *ProviderSpecificProperty) DeepCopy() *ProviderSpecificProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderSpecificProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicView) DeepCopyInto(out *PublicView) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicView.
+func (in *PublicView) DeepCopy() *PublicView {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicView)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceDiscoveryRule) DeepCopyInto(out *ServiceDiscoveryRule) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.TXT != nil {
+		in, out := &in.TXT, &out.TXT
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDiscoveryRule.
+func (in *ServiceDiscoveryRule) DeepCopy() *ServiceDiscoveryRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceDiscoveryRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMapping) DeepCopyInto(out *ServiceMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMapping.
+func (in *ServiceMapping) DeepCopy() *ServiceMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SkippedStatus) DeepCopyInto(out *SkippedStatus) {
+	*out = *in
+	if in.Sample != nil {
+		in, out := &in.Sample, &out.Sample
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SkippedStatus.
+func (in *SkippedStatus) DeepCopy() *SkippedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SkippedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubdomainRule) DeepCopyInto(out *SubdomainRule) {
+	*out = *in
+	if in.VLAN != nil {
+		in, out := &in.VLAN, &out.VLAN
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubdomainRule.
+func (in *SubdomainRule) DeepCopy() *SubdomainRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SubdomainRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subdomains) DeepCopyInto(out *Subdomains) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SubdomainRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subdomains.
+func (in *Subdomains) DeepCopy() *Subdomains {
+	if in == nil {
+		return nil
+	}
+	out := new(Subdomains)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Uplinks) DeepCopyInto(out *Uplinks) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Uplinks.
+func (in *Uplinks) DeepCopy() *Uplinks {
+	if in == nil {
+		return nil
+	}
+	out := new(Uplinks)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,162 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// skippedSampleSize limits the number of skipped addresses reported in the
+// status
+const skippedSampleSize = 10
+
+// addressFilter decides which client addresses may be published
+type addressFilter struct {
+	allowed  []*net.IPNet
+	excluded []*net.IPNet
+}
+
+func newAddressFilter(spec *dnsv1alpha1.MerakiSourceSpec) (*addressFilter, error) {
+	f := &addressFilter{}
+	for _, cidr := range spec.AllowedCIDRs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed CIDR: %v", err)
+		}
+		f.allowed = append(f.allowed, n)
+	}
+	for _, cidr := range spec.ExcludedCIDRs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid excluded CIDR: %v", err)
+		}
+		f.excluded = append(f.excluded, n)
+	}
+	return f, nil
+}
+
+// check returns the reason the IPv4 address may not be published, or an
+// empty string if it may
+func (f *addressFilter) check(address string) string {
+	if address == "" {
+		return "no IP address"
+	}
+	ip := net.ParseIP(address)
+	if ip == nil || ip.To4() == nil {
+		return fmt.Sprintf("invalid IPv4 address %q", address)
+	}
+	return f.checkIP(ip, address)
+}
+
+// check6 returns the reason the IPv6 address may not be published, or an
+// empty string if it may
+func (f *addressFilter) check6(address string) string {
+	if address == "" {
+		return "no IPv6 address"
+	}
+	ip := net.ParseIP(address)
+	if ip == nil || ip.To4() != nil {
+		return fmt.Sprintf("invalid IPv6 address %q", address)
+	}
+	return f.checkIP(ip, address)
+}
+
+// checkIP checks a parsed address against the CIDRs. Once allowed CIDRs are
+// set, addresses of an IP family without an allowed CIDR are rejected
+func (f *addressFilter) checkIP(ip net.IP, address string) string {
+	if ip.IsLinkLocalUnicast() {
+		return fmt.Sprintf("link-local address %s", address)
+	}
+	if ip.IsUnspecified() || ip.IsLoopback() {
+		return fmt.Sprintf("unusable address %s", address)
+	}
+	for _, n := range f.excluded {
+		if n.Contains(ip) {
+			return fmt.Sprintf("address %s is in excluded CIDR %s", address, n)
+		}
+	}
+	if len(f.allowed) == 0 {
+		return ""
+	}
+	for _, n := range f.allowed {
+		if n.Contains(ip) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("address %s is outside the allowed CIDRs", address)
+}
+
+// filterClients returns a copy of the snapshot with only the clients that have
+// an address that may be published, and a summary of the skipped clients and
+// addresses. IPv4 and IPv6 addresses are checked separately, and an address
+// that may not be published is cleared on a copy of the client. The cached
+// snapshot is not modified
+func filterClients(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot) (*Snapshot, *dnsv1alpha1.SkippedStatus, error) {
+	f, err := newAddressFilter(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filtered := snapshot.filtered()
+	var skipped *dnsv1alpha1.SkippedStatus
+	skip := func(client *meraki.Client, reason string) {
+		if skipped == nil {
+			skipped = &dnsv1alpha1.SkippedStatus{}
+		}
+		skipped.Addresses++
+		if len(skipped.Sample) < skippedSampleSize {
+			skipped.Sample = append(skipped.Sample, client.Mac+": "+reason)
+		}
+	}
+
+	for _, client := range snapshot.Clients {
+		reason := f.check(client.IP)
+		reason6 := ""
+		if client.IP6 != "" {
+			reason6 = f.check6(client.IP6)
+		}
+
+		if reason == "" && reason6 == "" {
+			filtered.Clients = append(filtered.Clients, client)
+			continue
+		}
+		if reason != "" {
+			skip(client, reason)
+		}
+		if reason6 != "" {
+			skip(client, reason6)
+		}
+
+		if reason == "" || (client.IP6 != "" && reason6 == "") {
+			c := *client
+			if reason != "" {
+				c.IP = ""
+			}
+			if reason6 != "" {
+				c.IP6 = ""
+			}
+			filtered.Clients = append(filtered.Clients, &c)
+			continue
+		}
+		skipped.Clients++
+	}
+	return filtered, skipped, nil
+}
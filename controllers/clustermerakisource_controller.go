@@ -0,0 +1,63 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// ClusterMerakiSourceReconciler reconciles a ClusterMerakiSource object. It
+// shares the configuration and sync logic of the MerakiSourceReconciler
+type ClusterMerakiSourceReconciler struct {
+	*MerakiSourceReconciler
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=dns.jossware.com,resources=clustermerakisources,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=dns.jossware.com,resources=clustermerakisources/status,verbs=get;update;patch
+
+func (r *ClusterMerakiSourceReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	log := r.Log.WithValues("clustermerakisource", req.Name)
+
+	var source dnsv1alpha1.ClusterMerakiSource
+	if err := r.Get(ctx, req.NamespacedName, &source); err != nil {
+		if apierrs.IsNotFound(err) {
+			// 404, wait for next notification
+			log.V(1).Info("not found")
+			r.snapshots.delete(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch ClusterMerakiSource")
+		return ctrl.Result{}, err
+	}
+
+	return r.reconcileSource(ctx, log, &source)
+}
+
+func (r *ClusterMerakiSourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dnsv1alpha1.ClusterMerakiSource{}).
+		Owns(&endpoint.DNSEndpoint{}).
+		Complete(r)
+}
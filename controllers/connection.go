@@ -0,0 +1,117 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// connectionClient returns a Meraki API client with the credentials and
+// settings of a MerakiConnection. Secrets are read from the namespace of the
+// connection. The settings of the connection override the controller options
+func connectionClient(ctx context.Context, reader client.Reader, conn *dnsv1alpha1.MerakiConnection, defaultRegion string, limiter *rate.Limiter, options ...func(*meraki.Api)) (*meraki.Api, error) {
+	apiKey, err := secretValue(ctx, reader, conn.Namespace, &conn.Spec.APIKeySecretRef)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == "" {
+		return nil, errors.New("a Meraki API key is required")
+	}
+
+	region, err := regionOption(conn.Spec.Region, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := append(append([]func(*meraki.Api){}, options...), region)
+	if conn.Spec.BaseURL != "" {
+		opts = append(opts, meraki.BaseURL(conn.Spec.BaseURL))
+	}
+	if conn.Spec.Proxy != "" {
+		proxyURL, err := url.Parse(conn.Spec.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %v", err)
+		}
+		opts = append(opts, meraki.Proxy(proxyURL))
+	}
+	if ref := conn.Spec.ProxyCredentialsSecretRef; ref != nil {
+		credentials, err := secretValue(ctx, reader, conn.Namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		proxyCredentials, err := meraki.ProxyCredentials(credentials)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, proxyCredentials)
+	}
+	if limiter != nil {
+		opts = append(opts, meraki.RateLimiter(limiter))
+	}
+
+	return meraki.New(apiKey, opts...), nil
+}
+
+// regionOption returns the client option for a dashboard region, or for the
+// controller default region if it is not set
+func regionOption(region dnsv1alpha1.Region, defaultRegion string) (func(*meraki.Api), error) {
+	if region == "" {
+		return meraki.Region(defaultRegion)
+	}
+	return meraki.Region(string(region))
+}
+
+// rateLimiters holds a rate limiter for each MerakiConnection so every source
+// using a connection shares its limit
+type rateLimiters struct {
+	mu       sync.Mutex
+	limiters map[types.NamespacedName]*rate.Limiter
+}
+
+// get returns the limiter for the connection, or nil if the connection is not
+// rate limited. The limit is updated if the connection has changed
+func (l *rateLimiters) get(conn *dnsv1alpha1.MerakiConnection) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := types.NamespacedName{Namespace: conn.Namespace, Name: conn.Name}
+	if conn.Spec.RateLimit == nil {
+		delete(l.limiters, key)
+		return nil
+	}
+
+	limit := rate.Limit(*conn.Spec.RateLimit)
+	limiter, ok := l.limiters[key]
+	if !ok || limiter.Limit() != limit {
+		if l.limiters == nil {
+			l.limiters = map[types.NamespacedName]*rate.Limiter{}
+		}
+		limiter = rate.NewLimiter(limit, int(*conn.Spec.RateLimit))
+		l.limiters[key] = limiter
+	}
+	return limiter
+}
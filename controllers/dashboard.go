@@ -0,0 +1,117 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// settings that can be set with tags and notes in the Meraki dashboard
+const (
+	dashboardSettingPrefix = "dns-"
+
+	settingDomain        = "dns-domain"
+	settingTTL           = "dns-ttl"
+	settingAllowedCIDRs  = "dns-allowed-cidrs"
+	settingExcludedCIDRs = "dns-excluded-cidrs"
+)
+
+// hasDashboardConfig returns true if the source reads settings from the
+// Meraki network
+func hasDashboardConfig(spec *dnsv1alpha1.MerakiSourceSpec) bool {
+	return spec.DashboardConfig != nil
+}
+
+// dashboardSettings returns the dns- settings in the tags and notes of a
+// network. Notes are read after the tags so their settings win
+func dashboardSettings(network *meraki.Network) map[string]string {
+	fields := append(append([]string{}, network.Tags...), strings.Fields(network.Notes)...)
+
+	settings := map[string]string{}
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToLower(parts[0])
+		if !strings.HasPrefix(key, dashboardSettingPrefix) {
+			continue
+		}
+		settings[key] = parts[1]
+	}
+	return settings
+}
+
+// splitList splits a comma separated setting
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// applyDashboardConfig returns a copy of the spec with the settings from the
+// Meraki dashboard applied, and the effective configuration to report in the
+// status. The spec of the source is not modified
+func applyDashboardConfig(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot) (*dnsv1alpha1.MerakiSourceSpec, *dnsv1alpha1.EffectiveConfig, error) {
+	if !hasDashboardConfig(spec) {
+		return spec, nil, nil
+	}
+
+	effective := spec.DeepCopy()
+	override := spec.DashboardConfig.Precedence != dnsv1alpha1.DashboardPrecedenceDefault
+	settings := snapshot.DashboardSettings
+
+	var from []string
+	if v, ok := settings[settingDomain]; ok && (override || effective.Domain == "") {
+		effective.Domain = strings.TrimSuffix(v, ".")
+		from = append(from, settingDomain)
+	}
+	if v, ok := settings[settingTTL]; ok && (override || effective.TTL == nil) {
+		ttl, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || ttl < 0 {
+			return nil, nil, fmt.Errorf("invalid %s %q in the Meraki dashboard", settingTTL, v)
+		}
+		effective.TTL = &ttl
+		from = append(from, settingTTL)
+	}
+	if v, ok := settings[settingAllowedCIDRs]; ok && (override || len(effective.AllowedCIDRs) == 0) {
+		effective.AllowedCIDRs = splitList(v)
+		from = append(from, settingAllowedCIDRs)
+	}
+	if v, ok := settings[settingExcludedCIDRs]; ok && (override || len(effective.ExcludedCIDRs) == 0) {
+		effective.ExcludedCIDRs = splitList(v)
+		from = append(from, settingExcludedCIDRs)
+	}
+
+	config := &dnsv1alpha1.EffectiveConfig{
+		Domain:        effective.Domain,
+		TTL:           effective.TTL,
+		AllowedCIDRs:  effective.AllowedCIDRs,
+		ExcludedCIDRs: effective.ExcludedCIDRs,
+		FromDashboard: from,
+	}
+	return effective, config, nil
+}
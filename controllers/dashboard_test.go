@@ -0,0 +1,156 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+func TestDashboardSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		network meraki.Network
+		want    map[string]string
+	}{
+		{
+			name: "tags and notes",
+			network: meraki.Network{
+				Tags:  meraki.Tags{"branch", "dns-domain=tags.example.com", "DNS-TTL=60"},
+				Notes: "Branch office\ndns-allowed-cidrs=10.0.0.0/8,192.168.0.0/16",
+			},
+			want: map[string]string{
+				settingDomain:       "tags.example.com",
+				settingTTL:          "60",
+				settingAllowedCIDRs: "10.0.0.0/8,192.168.0.0/16",
+			},
+		},
+		{
+			name: "notes win over tags",
+			network: meraki.Network{
+				Tags:  meraki.Tags{"dns-domain=tags.example.com"},
+				Notes: "dns-domain=notes.example.com",
+			},
+			want: map[string]string{settingDomain: "notes.example.com"},
+		},
+		{
+			name: "malformed notes",
+			network: meraki.Network{
+				Tags:  meraki.Tags{"dns-ttl=60"},
+				Notes: "dns-domain = office.example.com\ndns-ttl\n=60 domain=other.example.com",
+			},
+			want: map[string]string{settingTTL: "60"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dashboardSettings(&tt.network)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got settings %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDashboardConfig(t *testing.T) {
+	ttl := int64(300)
+	dashboardTTL := int64(60)
+	settings := map[string]string{
+		settingDomain:       "dashboard.example.com.",
+		settingTTL:          "60",
+		settingAllowedCIDRs: "10.0.0.0/8, 192.168.0.0/16",
+	}
+
+	tests := []struct {
+		name       string
+		precedence dnsv1alpha1.DashboardPrecedence
+		settings   map[string]string
+		want       *dnsv1alpha1.EffectiveConfig
+		wantErr    bool
+	}{
+		{
+			name:     "override by default",
+			settings: settings,
+			want: &dnsv1alpha1.EffectiveConfig{
+				Domain:        "dashboard.example.com",
+				TTL:           &dashboardTTL,
+				AllowedCIDRs:  []string{"10.0.0.0/8", "192.168.0.0/16"},
+				ExcludedCIDRs: []string{"10.99.0.0/16"},
+				FromDashboard: []string{settingDomain, settingTTL, settingAllowedCIDRs},
+			},
+		},
+		{
+			name:       "default only fills unset fields",
+			precedence: dnsv1alpha1.DashboardPrecedenceDefault,
+			settings:   settings,
+			want: &dnsv1alpha1.EffectiveConfig{
+				Domain:        "office.example.com",
+				TTL:           &ttl,
+				AllowedCIDRs:  []string{"10.0.0.0/8", "192.168.0.0/16"},
+				ExcludedCIDRs: []string{"10.99.0.0/16"},
+				FromDashboard: []string{settingAllowedCIDRs},
+			},
+		},
+		{
+			name:     "no settings",
+			settings: map[string]string{},
+			want: &dnsv1alpha1.EffectiveConfig{
+				Domain:        "office.example.com",
+				TTL:           &ttl,
+				ExcludedCIDRs: []string{"10.99.0.0/16"},
+			},
+		},
+		{
+			name:     "invalid TTL",
+			settings: map[string]string{settingTTL: "soon"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &dnsv1alpha1.MerakiSourceSpec{
+				Domain:          "office.example.com",
+				TTL:             &ttl,
+				ExcludedCIDRs:   []string{"10.99.0.0/16"},
+				DashboardConfig: &dnsv1alpha1.DashboardConfig{Precedence: tt.precedence},
+			}
+			original := spec.DeepCopy()
+
+			effective, config, err := applyDashboardConfig(spec, &Snapshot{DashboardSettings: tt.settings})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(spec, original) {
+				t.Errorf("the spec of the source was modified")
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(config, tt.want) {
+				t.Errorf("got effective config %+v, want %+v", config, tt.want)
+			}
+			if effective.Domain != config.Domain || *effective.TTL != *config.TTL {
+				t.Errorf("got spec domain %q and TTL %d, want the effective config", effective.Domain, *effective.TTL)
+			}
+		})
+	}
+}
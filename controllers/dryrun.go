@@ -0,0 +1,104 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// dryRunSampleSize limits the number of records and differences reported in
+// the dry run status
+const dryRunSampleSize = 10
+
+// dryRunStatus compares the desired records with the current records
+func dryRunStatus(current, desired []*endpoint.Endpoint) *dnsv1alpha1.DryRunStatus {
+	status := &dnsv1alpha1.DryRunStatus{
+		Endpoints: len(desired),
+	}
+
+	existing := map[string]*endpoint.Endpoint{}
+	for _, e := range current {
+		existing[endpointKey(e)] = e
+	}
+
+	seen := map[string]bool{}
+	for _, e := range desired {
+		if len(status.Sample) < dryRunSampleSize {
+			status.Sample = append(status.Sample, e.String())
+		}
+
+		key := endpointKey(e)
+		seen[key] = true
+		old, ok := existing[key]
+		switch {
+		case !ok:
+			status.Added++
+			status.Diff = appendDiff(status.Diff, "+ "+e.String())
+		case !sameEndpoint(old, e):
+			status.Changed++
+			status.Diff = appendDiff(status.Diff, "~ "+e.String())
+		}
+	}
+
+	for _, e := range current {
+		if !seen[endpointKey(e)] {
+			status.Removed++
+			status.Diff = appendDiff(status.Diff, "- "+e.String())
+		}
+	}
+
+	return status
+}
+
+func appendDiff(diff []string, line string) []string {
+	if len(diff) >= dryRunSampleSize {
+		return diff
+	}
+	return append(diff, line)
+}
+
+func endpointKey(e *endpoint.Endpoint) string {
+	return e.DNSName + " " + e.RecordType
+}
+
+// sameEndpoint returns true if the records have the same targets, TTL, labels
+// and provider specific properties
+func sameEndpoint(a, b *endpoint.Endpoint) bool {
+	if a.RecordTTL != b.RecordTTL || !sameTargets(a.Targets, b.Targets) {
+		return false
+	}
+	if len(a.Labels) != len(b.Labels) || len(a.ProviderSpecific) != len(b.ProviderSpecific) {
+		return false
+	}
+	for k, v := range a.Labels {
+		if b.Labels[k] != v {
+			return false
+		}
+	}
+	for _, p := range a.ProviderSpecific {
+		if q, ok := b.GetProviderSpecificProperty(p.Name); !ok || q.Value != p.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// sameTargets compares targets without reordering the targets of the records
+func sameTargets(a, b endpoint.Targets) bool {
+	return append(endpoint.Targets{}, a...).Same(append(endpoint.Targets{}, b...))
+}
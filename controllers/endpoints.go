@@ -0,0 +1,303 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// clientEndpoints returns the records to publish for the client
+func clientEndpoints(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot, client *meraki.Client) ([]*endpoint.Endpoint, error) {
+	override := clientOverride(spec, client)
+	name, ok := clientName(spec, snapshot, client)
+	if !ok || net.ParseIP(client.IP) == nil {
+		return nil, nil
+	}
+
+	domain, ttl := clientDomain(spec, client)
+	if override != nil && override.TTL != nil {
+		ttl = override.TTL
+	}
+
+	e := endpoint.NewEndpoint(name+"."+domain, endpoint.RecordTypeA, client.IP)
+	endpoints := []*endpoint.Endpoint{e}
+	if override != nil {
+		for _, alias := range override.Aliases {
+			if override.AliasType == endpoint.RecordTypeA {
+				endpoints = append(endpoints, endpoint.NewEndpoint(alias+"."+domain, endpoint.RecordTypeA, client.IP))
+			} else {
+				endpoints = append(endpoints, endpoint.NewEndpoint(alias+"."+domain, endpoint.RecordTypeCNAME, e.DNSName))
+			}
+		}
+	}
+
+	if spec.MetadataRecords != nil {
+		if m := metadataEndpoint(spec.MetadataRecords, e.DNSName, client); m != nil {
+			endpoints = append(endpoints, m)
+		}
+	}
+
+	for _, e := range endpoints {
+		if ttl != nil {
+			e.RecordTTL = endpoint.TTL(*ttl)
+		}
+	}
+	if err := setEndpointOptions(spec, override, client, endpoints); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// setEndpointOptions sets the source and override labels and provider
+// specific properties on the endpoints, rendered with the template data.
+// Records that are not about a single client or uplink have no data, and
+// values with templates are left out of them
+func setEndpointOptions(spec *dnsv1alpha1.MerakiSourceSpec, override *dnsv1alpha1.ClientOverride, data interface{}, endpoints []*endpoint.Endpoint) error {
+	labels, err := endpointLabels(spec, override, data)
+	if err != nil {
+		return err
+	}
+	properties, err := providerSpecific(spec, override, data)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range endpoints {
+		for k, v := range labels {
+			e.Labels[k] = v
+		}
+		for _, p := range properties {
+			e.WithProviderSpecific(p.Name, p.Value)
+		}
+	}
+	return nil
+}
+
+// endpointLabels returns the source and override labels rendered with the
+// template data
+func endpointLabels(spec *dnsv1alpha1.MerakiSourceSpec, override *dnsv1alpha1.ClientOverride, data interface{}) (map[string]string, error) {
+	labels := map[string]string{}
+	sources := []map[string]string{spec.EndpointLabels}
+	if override != nil {
+		sources = append(sources, override.Labels)
+	}
+	for _, source := range sources {
+		for k, v := range source {
+			if data == nil && hasTemplate(v) {
+				continue
+			}
+			value, err := renderTemplate(v, data)
+			if err != nil {
+				return nil, fmt.Errorf("label %s: %v", k, err)
+			}
+			labels[k] = value
+		}
+	}
+	return labels, nil
+}
+
+// providerSpecific returns the source and override provider specific
+// properties rendered with the template data. Override properties replace source properties
+// with the same name
+func providerSpecific(spec *dnsv1alpha1.MerakiSourceSpec, override *dnsv1alpha1.ClientOverride, data interface{}) (endpoint.ProviderSpecific, error) {
+	properties := spec.ProviderSpecific
+	if override != nil && len(override.ProviderSpecific) > 0 {
+		properties = nil
+		for _, p := range spec.ProviderSpecific {
+			if !hasProperty(override.ProviderSpecific, p.Name) {
+				properties = append(properties, p)
+			}
+		}
+		properties = append(properties, override.ProviderSpecific...)
+	}
+
+	var rendered endpoint.ProviderSpecific
+	for _, p := range properties {
+		if data == nil && hasTemplate(p.Value) {
+			continue
+		}
+		value, err := renderTemplate(p.Value, data)
+		if err != nil {
+			return nil, fmt.Errorf("provider specific property %s: %v", p.Name, err)
+		}
+		rendered = append(rendered, endpoint.ProviderSpecificProperty{Name: p.Name, Value: value})
+	}
+	return rendered, nil
+}
+
+func hasProperty(properties []dnsv1alpha1.ProviderSpecificProperty, name string) bool {
+	for _, p := range properties {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// renderTemplate renders value as a Go template with the client, or other
+// Meraki object, as data. Values without template actions are returned as is
+func renderTemplate(value string, data interface{}) (string, error) {
+	if !hasTemplate(value) {
+		return value, nil
+	}
+
+	t, err := template.New("value").Parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// metadataEndpoint returns a TXT record for the client record with the
+// requested client fields
+func metadataEndpoint(records *dnsv1alpha1.MetadataRecords, dnsName string, client *meraki.Client) *endpoint.Endpoint {
+	fields := records.Fields
+	if len(fields) == 0 {
+		// lastSeen changes on every sync, and would rewrite every record
+		// each time, so it has to be asked for
+		fields = []dnsv1alpha1.MetadataField{
+			dnsv1alpha1.MetadataFieldMAC,
+			dnsv1alpha1.MetadataFieldManufacturer,
+			dnsv1alpha1.MetadataFieldOS,
+			dnsv1alpha1.MetadataFieldVLAN,
+			dnsv1alpha1.MetadataFieldDeviceName,
+		}
+	}
+
+	var targets []string
+	for _, field := range fields {
+		var value string
+		switch field {
+		case dnsv1alpha1.MetadataFieldMAC:
+			value = client.Mac
+		case dnsv1alpha1.MetadataFieldManufacturer:
+			value = client.Manufacturer
+		case dnsv1alpha1.MetadataFieldOS:
+			value = client.Os
+		case dnsv1alpha1.MetadataFieldVLAN:
+			if client.Vlan != 0 {
+				value = strconv.Itoa(client.Vlan)
+			}
+		case dnsv1alpha1.MetadataFieldDeviceName:
+			value = client.RecentDeviceName
+		case dnsv1alpha1.MetadataFieldLastSeen:
+			if !client.LastSeen.IsZero() {
+				value = client.LastSeen.UTC().Format(time.RFC3339)
+			}
+		}
+		if value != "" {
+			targets = append(targets, string(field)+"="+value)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+	return endpoint.NewEndpoint(metadataPrefix(records)+dnsName, endpoint.RecordTypeTXT, targets...)
+}
+
+func metadataPrefix(records *dnsv1alpha1.MetadataRecords) string {
+	if records.Prefix == "" {
+		return dnsv1alpha1.DefaultMetadataPrefix
+	}
+	return records.Prefix
+}
+
+// clientName returns the name of the client record taking the name sources
+// and any override into account. It returns false if the client is excluded
+func clientName(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot, client *meraki.Client) (string, bool) {
+	override := clientOverride(spec, client)
+	if override == nil {
+		return baseName(spec, snapshot, client), true
+	}
+	if override.Exclude {
+		return "", false
+	}
+	if override.Name != "" {
+		return override.Name, true
+	}
+	return baseName(spec, snapshot, client), true
+}
+
+// clientOverride returns the override matching the client, if any
+func clientOverride(spec *dnsv1alpha1.MerakiSourceSpec, client *meraki.Client) *dnsv1alpha1.ClientOverride {
+	for i := range spec.Overrides {
+		o := &spec.Overrides[i]
+		if o.MAC != "" && strings.EqualFold(o.MAC, client.Mac) {
+			return o
+		}
+		if o.ClientID != "" && o.ClientID == client.ID {
+			return o
+		}
+	}
+	return nil
+}
+
+// clientDomain returns the domain and TTL to use for the client taking any
+// subdomain rules into account
+func clientDomain(spec *dnsv1alpha1.MerakiSourceSpec, client *meraki.Client) (string, *int64) {
+	if spec.Subdomains == nil {
+		return spec.Domain, spec.TTL
+	}
+
+	for _, rule := range spec.Subdomains.Rules {
+		if rule.VLAN == nil && rule.SSID == "" {
+			continue
+		}
+		if rule.VLAN != nil && int(*rule.VLAN) != client.Vlan {
+			continue
+		}
+		if rule.SSID != "" && rule.SSID != client.Ssid {
+			continue
+		}
+
+		ttl := spec.TTL
+		if rule.TTL != nil {
+			ttl = rule.TTL
+		}
+		return subdomain(rule.Label, spec.Domain), ttl
+	}
+
+	return subdomain(spec.Subdomains.Default, spec.Domain), spec.TTL
+}
+
+func subdomain(label, domain string) string {
+	if label == "" {
+		return domain
+	}
+	return label + "." + domain
+}
@@ -0,0 +1,174 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// recordTypeAAAA is not defined by the external-dns version we build against
+const recordTypeAAAA = "AAAA"
+
+// clientMatcher matches clients against a ClientSelector
+type clientMatcher struct {
+	selector    *dnsv1alpha1.ClientSelector
+	description *regexp.Regexp
+}
+
+func newClientMatcher(selector *dnsv1alpha1.ClientSelector) (*clientMatcher, error) {
+	m := &clientMatcher{selector: selector}
+	if selector.DescriptionRegex != "" {
+		re, err := regexp.Compile(selector.DescriptionRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid description regex: %v", err)
+		}
+		m.description = re
+	}
+	return m, nil
+}
+
+func (m *clientMatcher) matches(client *meraki.Client) bool {
+	s := m.selector
+	if len(s.Manufacturers) > 0 && !containsFold(s.Manufacturers, client.Manufacturer) {
+		return false
+	}
+	if len(s.OperatingSystems) > 0 {
+		found := false
+		for _, os := range s.OperatingSystems {
+			if strings.Contains(strings.ToLower(client.Os), strings.ToLower(os)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(s.VLANs) > 0 {
+		found := false
+		for _, vlan := range s.VLANs {
+			if int(vlan) == client.Vlan {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if m.description != nil && !m.description.MatchString(client.Description) {
+		return false
+	}
+	if len(s.MACs) > 0 && !containsFold(s.MACs, client.Mac) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupMatchers returns a matcher for each group of the source
+func groupMatchers(spec *dnsv1alpha1.MerakiSourceSpec) ([]*clientMatcher, error) {
+	var matchers []*clientMatcher
+	for i := range spec.Groups {
+		m, err := newClientMatcher(&spec.Groups[i].Selector)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %v", spec.Groups[i].Name, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// groupMember returns true if the client is a member of a group that
+// excludes the records of its members
+func groupMember(spec *dnsv1alpha1.MerakiSourceSpec, matchers []*clientMatcher, client *meraki.Client) bool {
+	for i, m := range matchers {
+		if spec.Groups[i].ExcludeMembers && m.matches(client) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupEndpoints returns an A record with the IPv4 addresses, and an AAAA
+// record with the IPv6 addresses, of the members of each group. Clients
+// excluded with an override are not members of any group
+func groupEndpoints(spec *dnsv1alpha1.MerakiSourceSpec, matchers []*clientMatcher, snapshot *Snapshot) ([]*endpoint.Endpoint, error) {
+	var endpoints []*endpoint.Endpoint
+	for i, m := range matchers {
+		group := spec.Groups[i]
+
+		var ipv4, ipv6 []string
+		for _, client := range snapshot.Clients {
+			if _, ok := clientName(spec, snapshot, client); !ok || !m.matches(client) {
+				continue
+			}
+			if ip := net.ParseIP(client.IP); ip != nil && ip.To4() != nil && !containsString(ipv4, client.IP) {
+				ipv4 = append(ipv4, client.IP)
+			}
+			if ip := net.ParseIP(client.IP6); ip != nil && ip.To4() == nil && !containsString(ipv6, client.IP6) {
+				ipv6 = append(ipv6, client.IP6)
+			}
+		}
+
+		ttl := spec.TTL
+		if group.TTL != nil {
+			ttl = group.TTL
+		}
+
+		name := group.Name + "." + spec.Domain
+		for _, record := range []struct {
+			recordType string
+			targets    []string
+		}{
+			{endpoint.RecordTypeA, ipv4},
+			{recordTypeAAAA, ipv6},
+		} {
+			if len(record.targets) == 0 {
+				continue
+			}
+			// keep the order stable so the DNSEndpoint only changes when the
+			// members do
+			sort.Strings(record.targets)
+			e := endpoint.NewEndpoint(name, record.recordType, record.targets...)
+			if ttl != nil {
+				e.RecordTTL = endpoint.TTL(*ttl)
+			}
+			endpoints = append(endpoints, e)
+		}
+	}
+	if err := setEndpointOptions(spec, nil, nil, endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,116 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+func TestGroupEndpointsAddresses(t *testing.T) {
+	clients := []*meraki.Client{
+		{ID: "a", Mac: "00:00:00:00:00:01", Manufacturer: "Brother", IP: "10.0.0.1", IP6: "2001:db8::1"},
+		{ID: "b", Mac: "00:00:00:00:00:02", Manufacturer: "Brother", IP6: "2001:db8::2"},
+		{ID: "c", Mac: "00:00:00:00:00:03", Manufacturer: "Brother", IP: "10.0.0.3", IP6: "fe80::3"},
+		{ID: "d", Mac: "00:00:00:00:00:04", Manufacturer: "Brother", IP: "10.0.0.4", IP6: "not-an-address"},
+		{ID: "e", Mac: "00:00:00:00:00:05", Manufacturer: "Brother", IP: "169.254.0.5", IP6: "2001:db8:ff::5"},
+	}
+
+	tests := []struct {
+		name      string
+		spec      dnsv1alpha1.MerakiSourceSpec
+		want      []string
+		skipped   int
+		addresses int
+	}{
+		{
+			name: "all addresses",
+			want: []string{
+				"printers.office.example.com A 10.0.0.1,10.0.0.3,10.0.0.4",
+				"printers.office.example.com AAAA 2001:db8::1,2001:db8::2,2001:db8:ff::5",
+			},
+			addresses: 4,
+		},
+		{
+			name: "allowed CIDRs of each family",
+			spec: dnsv1alpha1.MerakiSourceSpec{AllowedCIDRs: []string{"10.0.0.0/30", "2001:db8::/64"}},
+			want: []string{
+				"printers.office.example.com A 10.0.0.1,10.0.0.3",
+				"printers.office.example.com AAAA 2001:db8::1,2001:db8::2",
+			},
+			skipped:   2,
+			addresses: 6,
+		},
+		{
+			name: "IPv4 allowed CIDRs reject IPv6",
+			spec: dnsv1alpha1.MerakiSourceSpec{AllowedCIDRs: []string{"10.0.0.0/30"}},
+			want: []string{
+				"printers.office.example.com A 10.0.0.1,10.0.0.3",
+			},
+			skipped:   3,
+			addresses: 8,
+		},
+		{
+			name: "excluded IPv6 CIDR",
+			spec: dnsv1alpha1.MerakiSourceSpec{ExcludedCIDRs: []string{"2001:db8::/64"}},
+			want: []string{
+				"printers.office.example.com A 10.0.0.1,10.0.0.3,10.0.0.4",
+				"printers.office.example.com AAAA 2001:db8:ff::5",
+			},
+			skipped:   1,
+			addresses: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.spec
+			spec.Domain = "office.example.com"
+			spec.Groups = []dnsv1alpha1.Group{{
+				Name:     "printers",
+				Selector: dnsv1alpha1.ClientSelector{Manufacturers: []string{"Brother"}},
+			}}
+
+			snapshot, skipped, err := filterClients(&spec, &Snapshot{Clients: clients})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got dnsv1alpha1.SkippedStatus
+			if skipped != nil {
+				got = *skipped
+			}
+			if got.Clients != tt.skipped {
+				t.Errorf("got %d skipped clients, want %d", got.Clients, tt.skipped)
+			}
+			if got.Addresses != tt.addresses {
+				t.Errorf("got %d skipped addresses, want %d", got.Addresses, tt.addresses)
+			}
+
+			matchers, err := groupMatchers(&spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			endpoints, err := groupEndpoints(&spec, matchers, snapshot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			equalRecords(t, endpoints, tt.want)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// MerakiConnectionReconciler reconciles a MerakiConnection object. It checks
+// the credentials of the connection and records the organization they give
+// access to
+type MerakiConnectionReconciler struct {
+	client.Client
+	Log           logr.Logger
+	Scheme        *runtime.Scheme
+	APIReader     client.Reader
+	Region        string
+	APIOptions    []func(*meraki.Api)
+	CheckInterval time.Duration
+}
+
+// +kubebuilder:rbac:groups=dns.jossware.com,resources=merakiconnections,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=dns.jossware.com,resources=merakiconnections/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+func (r *MerakiConnectionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	log := r.Log.WithValues("merakiconnection", req.NamespacedName)
+
+	var conn dnsv1alpha1.MerakiConnection
+	if err := r.Get(ctx, req.NamespacedName, &conn); err != nil {
+		if apierrs.IsNotFound(err) {
+			// 404, wait for next notification
+			log.V(1).Info("not found")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch MerakiConnection")
+		return ctrl.Result{}, err
+	}
+
+	org, err := r.check(ctx, &conn)
+	if err != nil {
+		log.Info("connection check failed", "error", err.Error())
+		conn.Status.SetCondition(dnsv1alpha1.ConditionReady, corev1.ConditionFalse, "CheckFailed", err.Error())
+	} else {
+		conn.Status.Organization = *org
+		conn.Status.SetCondition(dnsv1alpha1.ConditionReady, corev1.ConditionTrue, "Connected",
+			fmt.Sprintf("connected to organization %s (%s)", org.Name, org.ID))
+	}
+	now := metav1.Now()
+	conn.Status.CheckedAt = &now
+
+	if err := r.Status().Update(ctx, &conn); err != nil {
+		if apierrs.IsConflict(err) {
+			log.V(1).Info("conflict updating status, requeueing")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Error(err, "unable to update MerakiConnection status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: wait.Jitter(r.CheckInterval, requeueJitter)}, nil
+}
+
+// check queries Meraki with the credentials of the connection and returns the
+// organization it refers to
+func (r *MerakiConnectionReconciler) check(ctx context.Context, conn *dnsv1alpha1.MerakiConnection) (*dnsv1alpha1.MerakiRef, error) {
+	merakiClient, err := connectionClient(ctx, r.APIReader, conn, r.Region, nil, r.APIOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := conn.Spec.Organization
+	switch {
+	case ref.ID != "":
+		org, err := merakiClient.Organization(ref.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &dnsv1alpha1.MerakiRef{ID: org.ID, Name: org.Name}, nil
+	case ref.Name != "":
+		org, err := merakiClient.FindOrganization(ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		if org == nil {
+			return nil, fmt.Errorf("%s organization not found. check your name or API key", ref.Name)
+		}
+		return &dnsv1alpha1.MerakiRef{ID: org.ID, Name: org.Name}, nil
+	default:
+		// no organization, only check that the API key works
+		if _, err := merakiClient.Organizations(); err != nil {
+			return nil, err
+		}
+		return &dnsv1alpha1.MerakiRef{}, nil
+	}
+}
+
+func (r *MerakiConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// status updates do not change the generation, so ignoring them keeps each
+	// check from triggering another one
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dnsv1alpha1.MerakiConnection{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
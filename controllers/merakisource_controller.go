@@ -24,9 +24,12 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	ref "k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,20 +37,47 @@ import (
 	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
 )
 
+// Source is implemented by the MerakiSource and ClusterMerakiSource kinds
+type Source interface {
+	runtime.Object
+	metav1.Object
+	GetSourceSpec() *dnsv1alpha1.MerakiSourceSpec
+	GetSourceStatus() *dnsv1alpha1.MerakiSourceStatus
+	GetTargetNamespace() string
+}
+
+// requeueJitter is the maximum fraction of the requeue interval that is added
+// to spread out syncs
+const requeueJitter = 0.1
+
 // MerakiSourceReconciler reconciles a MerakiSource object
 type MerakiSourceReconciler struct {
 	client.Client
 	Log                 logr.Logger
 	Scheme              *runtime.Scheme
+	APIReader           client.Reader
+	Namespace           string
 	APIKey              string
+	Region              string
+	APIOptions          []func(*meraki.Api)
 	APIThrottleInterval time.Duration
 	RequeueInterval     time.Duration
+	TXTPrefix           string
+	DryRun              bool
+	PolicyCacheTTL      time.Duration
+
+	snapshots snapshotCache
+	delays    fetchDelays
+	limiters  rateLimiters
+	policies  policyCache
 }
 
 // +kubebuilder:rbac:groups=dns.jossware.com,resources=merakisources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=dns.jossware.com,resources=merakisources/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints/status,verbs=get
+// +kubebuilder:rbac:groups=dns.jossware.com,resources=merakiconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
 
 func (r *MerakiSourceReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
@@ -59,43 +89,190 @@ func (r *MerakiSourceReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 		if apierrs.IsNotFound(err) {
 			// 404, wait for next notification
 			log.V(1).Info("not found")
+			r.snapshots.delete(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "unable to fetch MerakiSource")
 		return ctrl.Result{}, err
 	}
 
+	return r.reconcileSource(ctx, log, &source)
+}
+
+// reconcileSource syncs the dns endpoint of a MerakiSource or
+// ClusterMerakiSource
+func (r *MerakiSourceReconciler) reconcileSource(ctx context.Context, log logr.Logger, source Source) (ctrl.Result, error) {
+	spec := source.GetSourceSpec()
+	status := source.GetSourceStatus()
+	key := types.NamespacedName{Namespace: source.GetNamespace(), Name: source.GetName()}
+
+	if !source.GetDeletionTimestamp().IsZero() {
+		return r.finalize(ctx, log, source)
+	}
+
+	// a dry run never writes a dns endpoint, so there is nothing to clean up.
+	// a manager run only to preview changes must not leave finalizers behind
+	if !r.DryRun && !spec.DryRun && !containsString(source.GetFinalizers(), dnsv1alpha1.Finalizer) {
+		source.SetFinalizers(append(source.GetFinalizers(), dnsv1alpha1.Finalizer))
+		if err := r.Update(ctx, source); err != nil {
+			log.Error(err, "unable to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if spec.Suspend {
+		// leave the dns endpoint as is until we are resumed
+		log.V(1).Info("suspended")
+		status.SetCondition(dnsv1alpha1.ConditionSuspended, corev1.ConditionTrue, "Suspended", "syncing from Meraki is suspended")
+		return r.updateStatus(ctx, log, source, ctrl.Result{})
+	}
+	if status.GetCondition(dnsv1alpha1.ConditionSuspended) != nil {
+		status.SetCondition(dnsv1alpha1.ConditionSuspended, corev1.ConditionFalse, "Resumed", "syncing from Meraki has resumed")
+	}
+
 	var dnsEndpoint endpoint.DNSEndpoint
-	// dns endpoint will have the same name as the MerakiSource
-	if err := r.Get(ctx, req.NamespacedName, &dnsEndpoint); err != nil {
+	// dns endpoint will have the same name as the source
+	target := types.NamespacedName{Namespace: source.GetTargetNamespace(), Name: source.GetName()}
+	if err := r.Get(ctx, target, &dnsEndpoint); err != nil {
 		if apierrs.IsNotFound(err) {
 			log.V(1).Info("dns endpoint not found")
 			// create it
 			dnsEndpoint = endpoint.DNSEndpoint{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      source.Name,
-					Namespace: source.Namespace,
+					Name:      target.Name,
+					Namespace: target.Namespace,
 				},
 			}
 		} else {
-			log.Error(err, "unable to get dns endpoint", "dns-endpoint", req.NamespacedName)
+			log.Error(err, "unable to get dns endpoint", "dns-endpoint", target)
 			return ctrl.Result{}, err
 		}
 	}
 
-	if err := ctrl.SetControllerReference(&source, &dnsEndpoint, r.Scheme); err != nil {
+	if err := ctrl.SetControllerReference(source, &dnsEndpoint, r.Scheme); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// update the spec from MerakiData
-	// don't query meraki if we already did in the last 1 minute
-	if source.Status.SyncedAt == nil || time.Since(source.Status.SyncedAt.Time) > r.APIThrottleInterval {
-		endpoints, err := r.GetEndpoints(&source)
+	// only query meraki if we haven't within the throttle interval, unless a
+	// sync was explicitly requested. everything else, like spec changes or a
+	// deleted dns endpoint, is rendered from the last snapshot
+	snapshot := r.snapshots.get(key)
+	syncRequest, requested := syncRequested(source)
+	if snapshot == nil && !requested && !r.isNew(dnsEndpoint) && status.SyncedAt != nil {
+		// nothing is cached after a restart. leave the dns endpoint as is
+		// until the throttle interval has passed, plus a random share of it,
+		// so the sources don't all query meraki at once. this also applies
+		// when the controller was down for longer than the throttle interval
+		interval := r.throttleInterval(spec)
+		if delay := r.delays.until(key, status.SyncedAt.Add(interval), interval); delay > 0 {
+			log.V(1).Info("no cached meraki data, delaying the first query", "delay", delay)
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+	}
+	if requested || !snapshot.matches(spec) || status.SyncedAt == nil || time.Since(status.SyncedAt.Time) > r.throttleInterval(spec) {
+		fetched, err := r.Fetch(ctx, source)
 		if err != nil {
-			log.Error(err, "failed to get endpoints")
+			log.Error(err, "failed to fetch from meraki")
 			return ctrl.Result{}, err
 		}
+		snapshot = fetched
+		r.snapshots.set(key, snapshot)
+		r.delays.delete(key)
+
+		if requested {
+			log.V(1).Info("handled sync request", "request", syncRequest)
+			status.LastHandledSyncRequest = syncRequest
+		}
+
+		ts := metav1.NewTime(snapshot.FetchedAt)
+		status.SyncedAt = &ts
+	}
+
+	// settings from the Meraki dashboard apply to everything rendered from the
+	// snapshot. the spec of the source is left as is
+	spec, effective, err := applyDashboardConfig(spec, snapshot)
+	if err != nil {
+		log.Error(err, "failed to apply dashboard config")
+		return ctrl.Result{}, err
+	}
+	status.EffectiveConfig = effective
+
+	// clients filtered out by policy are left out of every record
+	snapshot = filterPolicies(spec, snapshot)
 
+	// clients without a usable address are left out of every record
+	snapshot, skipped, err := filterClients(spec, snapshot)
+	if err != nil {
+		log.Error(err, "failed to filter clients")
+		return ctrl.Result{}, err
+	}
+	if skipped != nil {
+		log.V(1).Info("skipped clients without a usable address", "clients", skipped.Clients)
+	}
+	status.Skipped = skipped
+
+	// metadata records that can't be told apart from the external-dns registry
+	// records are left out, the other records are still published
+	if msg := r.metadataRecordsBlocked(spec); msg != "" {
+		log.Info("not publishing metadata records", "reason", msg)
+		status.SetCondition(dnsv1alpha1.ConditionMetadataRecordsBlocked, corev1.ConditionTrue, "TXTPrefix", msg)
+		spec = spec.DeepCopy()
+		spec.MetadataRecords = nil
+	} else if status.GetCondition(dnsv1alpha1.ConditionMetadataRecordsBlocked) != nil {
+		status.SetCondition(dnsv1alpha1.ConditionMetadataRecordsBlocked, corev1.ConditionFalse, "TXTPrefix", "metadata records can be told apart from the external-dns registry records")
+	}
+
+	endpoints, err := r.GetEndpoints(spec, snapshot)
+	if err != nil {
+		log.Error(err, "failed to get endpoints")
+		return ctrl.Result{}, err
+	}
+
+	// the records of the views are written to their own dns endpoints but are
+	// protected and previewed like the main records
+	public, err := r.publicViewEndpoint(ctx, log, source, spec, snapshot)
+	if err != nil {
+		log.Error(err, "failed to get public view endpoints")
+		return ctrl.Result{}, err
+	}
+	translated, err := r.translatedViewEndpoint(ctx, log, source, spec, endpoints)
+	if err != nil {
+		log.Error(err, "failed to get translated endpoints")
+		return ctrl.Result{}, err
+	}
+	views := []*viewEndpoint{public, translated}
+
+	msg := deletionBlocked(spec, dnsEndpoint.Spec.Endpoints, endpoints)
+	for _, v := range views {
+		if msg == "" {
+			msg = v.deletionBlocked(spec)
+		}
+	}
+
+	if r.DryRun || spec.DryRun {
+		status.DryRun = dryRunStatus(dnsEndpoint.Spec.Endpoints, endpoints)
+		status.DryRun.Name = dnsEndpoint.GetName()
+		log.Info("dry run, not updating dns endpoint", "dns-endpoint", dnsEndpoint.GetName(),
+			"endpoints", status.DryRun.Endpoints,
+			"added", status.DryRun.Added,
+			"removed", status.DryRun.Removed,
+			"changed", status.DryRun.Changed)
+
+		status.DryRunViews = nil
+		for _, v := range views {
+			if viewStatus := r.viewDryRunStatus(source, v); viewStatus != nil {
+				status.DryRunViews = append(status.DryRunViews, *viewStatus)
+			}
+		}
+	} else if msg != "" && !allowDeletion(source) {
+		// keep the last known good records until the deletion is acknowledged
+		log.Info("refusing to remove records", "reason", msg)
+		status.SetCondition(dnsv1alpha1.ConditionDeletionBlocked, corev1.ConditionTrue, "TooManyDeletions",
+			fmt.Sprintf("%s. Set the %s annotation to apply the change", msg, dnsv1alpha1.AllowDeletionAnnotation))
+		return r.updateStatus(ctx, log, source, ctrl.Result{RequeueAfter: r.requeueInterval(spec)})
+	} else {
+		status.DryRun = nil
+		status.DryRunViews = nil
 		dnsEndpoint.Spec.Endpoints = endpoints
 
 		if r.isNew(dnsEndpoint) {
@@ -112,42 +289,133 @@ func (r *MerakiSourceReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 			log.V(1).Info("updated dns endpoint", "dns-endpoint", dnsEndpoint.GetName())
 		}
 
-		ts := metav1.Now()
-		source.Status.SyncedAt = &ts
+		if status.PublicEndpoint, err = r.syncViewEndpoint(ctx, log, source, public); err != nil {
+			return ctrl.Result{}, err
+		}
+		if status.TranslatedEndpoint, err = r.syncViewEndpoint(ctx, log, source, translated); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if status.GetCondition(dnsv1alpha1.ConditionDeletionBlocked) != nil {
+			status.SetCondition(dnsv1alpha1.ConditionDeletionBlocked, corev1.ConditionFalse, "Applied", "records are in sync")
+		}
+		if allowDeletion(source) {
+			// the acknowledgement only applies to a single sync, whether or
+			// not it was needed, so it can't approve a later mass deletion
+			observed := status.DeepCopy()
+			annotations := source.GetAnnotations()
+			delete(annotations, dnsv1alpha1.AllowDeletionAnnotation)
+			source.SetAnnotations(annotations)
+			if err := r.Update(ctx, source); err != nil {
+				log.Error(err, "unable to remove allow deletion annotation")
+				return ctrl.Result{}, err
+			}
+			*status = *observed
+		}
 	}
 
-	ref, err := ref.GetReference(r.Scheme, &dnsEndpoint)
-	if err != nil {
-		log.Error(err, "unable to make reference to dns endpoint", "dns-endpoint", dnsEndpoint)
+	if !r.isNew(dnsEndpoint) {
+		ref, err := ref.GetReference(r.Scheme, &dnsEndpoint)
+		if err != nil {
+			log.Error(err, "unable to make reference to dns endpoint", "dns-endpoint", dnsEndpoint)
+			return ctrl.Result{}, err
+		}
+		status.Endpoint = *ref
+	}
+
+	return r.updateStatus(ctx, log, source, ctrl.Result{RequeueAfter: r.requeueInterval(spec)})
+}
+
+// finalize applies the deletion policy to the dns endpoints owned by the
+// source and removes the finalizer
+func (r *MerakiSourceReconciler) finalize(ctx context.Context, log logr.Logger, source Source) (ctrl.Result, error) {
+	if !containsString(source.GetFinalizers(), dnsv1alpha1.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	key := types.NamespacedName{Namespace: source.GetNamespace(), Name: source.GetName()}
+	r.snapshots.delete(key)
+	r.delays.delete(key)
+
+	var dnsEndpoints endpoint.DNSEndpointList
+	if err := r.List(ctx, &dnsEndpoints, client.InNamespace(source.GetTargetNamespace())); err != nil {
+		log.Error(err, "unable to list dns endpoints")
+		return ctrl.Result{}, err
+	}
+
+	for i := range dnsEndpoints.Items {
+		dnsEndpoint := &dnsEndpoints.Items[i]
+		if !metav1.IsControlledBy(dnsEndpoint, source) {
+			continue
+		}
+
+		if source.GetSourceSpec().DeletionPolicy == dnsv1alpha1.DeletionPolicyOrphan {
+			// keep the records but let go of the dns endpoint so it is not
+			// garbage collected
+			var refs []metav1.OwnerReference
+			for _, ref := range dnsEndpoint.OwnerReferences {
+				if ref.UID != source.GetUID() {
+					refs = append(refs, ref)
+				}
+			}
+			dnsEndpoint.OwnerReferences = refs
+			if err := r.Update(ctx, dnsEndpoint); err != nil {
+				log.Error(err, "unable to orphan dns endpoint", "dns-endpoint", dnsEndpoint.GetName())
+				return ctrl.Result{}, err
+			}
+			log.V(1).Info("orphaned dns endpoint", "dns-endpoint", dnsEndpoint.GetName())
+			continue
+		}
+
+		if err := r.Delete(ctx, dnsEndpoint); err != nil && !apierrs.IsNotFound(err) {
+			log.Error(err, "unable to delete dns endpoint", "dns-endpoint", dnsEndpoint.GetName())
+			return ctrl.Result{}, err
+		}
+		log.V(1).Info("deleted dns endpoint", "dns-endpoint", dnsEndpoint.GetName())
+	}
+
+	source.SetFinalizers(removeString(source.GetFinalizers(), dnsv1alpha1.Finalizer))
+	if err := r.Update(ctx, source); err != nil {
+		log.Error(err, "unable to remove finalizer")
 		return ctrl.Result{}, err
 	}
-	source.Status.Endpoint = *ref
-	if err := r.Status().Update(ctx, &source); err != nil {
+
+	return ctrl.Result{}, nil
+}
+
+// updateStatus writes the source status and returns result if successful
+func (r *MerakiSourceReconciler) updateStatus(ctx context.Context, log logr.Logger, source Source, result ctrl.Result) (ctrl.Result, error) {
+	if err := r.Status().Update(ctx, source); err != nil {
 		if apierrs.IsConflict(err) {
-			log.V(1).Info("stale MerakiSource, requeue")
+			log.V(1).Info("stale source, requeue")
 			return ctrl.Result{Requeue: true}, nil
 		}
-		log.Error(err, "unable to update MerakiSource status")
+		log.Error(err, "unable to update source status")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+	return result, nil
 }
 
-func (r *MerakiSourceReconciler) GetEndpoints(source *dnsv1alpha1.MerakiSource) ([]*endpoint.Endpoint, error) {
-	merakiClient := meraki.New(r.APIKey)
+// Fetch queries Meraki for the data needed to render the source
+func (r *MerakiSourceReconciler) Fetch(ctx context.Context, source Source) (*Snapshot, error) {
+	spec := source.GetSourceSpec()
+	merakiClient, org, err := r.merakiClient(ctx, source)
+	if err != nil {
+		return nil, err
+	}
 
-	networkID := source.Spec.Network.ID
+	networkID := spec.Network.ID
 	if networkID == "" {
-		networkName := source.Spec.Network.Name
+		networkName := spec.Network.Name
 		if networkName == "" {
 			return nil, errors.New("network name or ID is required")
 		}
 
 		// make sure we have the organization ID
-		orgID := source.Spec.Organization.ID
+		orgID := org.ID
 		if orgID == "" {
-			orgName := source.Spec.Organization.Name
+			orgName := org.Name
 			if orgName == "" {
 				return nil, errors.New("organization name or ID is required")
 			}
@@ -178,22 +446,287 @@ func (r *MerakiSourceReconciler) GetEndpoints(source *dnsv1alpha1.MerakiSource)
 		networkID = network.ID
 	}
 
-	clients, err := merakiClient.Clients(networkID)
+	snapshot := &Snapshot{
+		Connection:   connectionName(spec),
+		Region:       spec.Region,
+		Organization: spec.Organization,
+		Network:      spec.Network,
+		Mode:         sourceMode(spec),
+		NetworkID:    networkID,
+	}
+
+	if hasDashboardConfig(spec) {
+		network, err := merakiClient.Network(networkID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Dashboard = true
+		snapshot.DashboardSettings = dashboardSettings(network)
+	}
+
+	switch snapshot.Mode {
+	case dnsv1alpha1.SourceModeUplinks:
+		devices, err := merakiClient.Devices(networkID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Uplinks = map[string][]*meraki.Uplink{}
+		for _, device := range devices {
+			if !device.IsAppliance() {
+				continue
+			}
+			uplinks, err := merakiClient.DeviceUplinks(networkID, device.Serial)
+			if err != nil {
+				return nil, fmt.Errorf("device %s: %v", device.Serial, err)
+			}
+			snapshot.Devices = append(snapshot.Devices, device)
+			snapshot.Uplinks[device.Serial] = uplinks
+		}
+	default:
+		clients, err := merakiClient.Clients(networkID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Clients = clients
+
+		if hasPublicView(spec) {
+			rules, err := merakiClient.OneToOneNatRules(networkID)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.PublicView = true
+			snapshot.NATRules = rules
+		}
+
+		if hasPortForwarding(spec) {
+			rules, err := merakiClient.PortForwardingRules(networkID)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.PortForwarding = true
+			snapshot.PortForwardingRules = rules
+		}
+
+		if hasGroupPolicyFilter(spec) {
+			policies, err := r.fetchPolicies(ctx, r.Log.WithValues("source", types.NamespacedName{Namespace: source.GetNamespace(), Name: source.GetName()}), merakiClient, networkID, clients)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.GroupPolicies = true
+			snapshot.Policies = policies
+		}
+
+		if usesSMNames(spec) {
+			devices, err := merakiClient.SMDevices(networkID)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.SystemsManager = true
+			snapshot.SMNames = smDeviceNames(devices)
+		}
+	}
+
+	snapshot.FetchedAt = time.Now()
+	return snapshot, nil
+}
+
+// GetEndpoints renders the records for the source from a snapshot of the
+// Meraki data
+func (r *MerakiSourceReconciler) GetEndpoints(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot) ([]*endpoint.Endpoint, error) {
+	if sourceMode(spec) == dnsv1alpha1.SourceModeUplinks {
+		return uplinkEndpoints(spec, snapshot)
+	}
+
+	matchers, err := groupMatchers(spec)
 	if err != nil {
 		return nil, err
 	}
 
 	var endpoints []*endpoint.Endpoint
-	for _, client := range clients {
-		e := endpoint.NewEndpoint(client.DNSName()+"."+source.Spec.Domain, "A", client.IP)
-		if source.Spec.TTL != nil {
-			e.RecordTTL = endpoint.TTL(*source.Spec.TTL)
+	for _, client := range snapshot.Clients {
+		if groupMember(spec, matchers, client) {
+			continue
+		}
+		records, err := clientEndpoints(spec, snapshot, client)
+		if err != nil {
+			return nil, fmt.Errorf("client %s: %v", client.Mac, err)
+		}
+		for _, e := range records {
+			r.Log.V(1).Info("found endpoint", "endpoint", e)
+			endpoints = append(endpoints, e)
 		}
-		r.Log.V(1).Info("found endpoint", "endpoint", e)
-		endpoints = append(endpoints, e)
 	}
 
-	return endpoints, nil
+	grouped, err := groupEndpoints(spec, matchers, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	endpoints = append(endpoints, grouped...)
+
+	services, err := serviceDiscoveryEndpoints(spec, matchers, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	endpoints = append(endpoints, services...)
+
+	if hasPortForwarding(spec) {
+		forwarded, err := portForwardingEndpoints(spec, matchers, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, forwarded...)
+	}
+
+	return applyAddressTranslation(spec, endpoints)
+}
+
+// merakiClient returns a Meraki API client with the credentials for the
+// source and the organization to query. Sources that reference a
+// MerakiConnection use its settings. ClusterMerakiSources may read their API
+// key from a Secret in the controller namespace
+func (r *MerakiSourceReconciler) merakiClient(ctx context.Context, source Source) (*meraki.Api, dnsv1alpha1.MerakiRef, error) {
+	spec := source.GetSourceSpec()
+	org := spec.Organization
+
+	if spec.ConnectionRef != nil {
+		namespace := source.GetNamespace()
+		if namespace == "" {
+			namespace = r.Namespace
+		}
+
+		var conn dnsv1alpha1.MerakiConnection
+		key := types.NamespacedName{Namespace: namespace, Name: spec.ConnectionRef.Name}
+		if err := r.Get(ctx, key, &conn); err != nil {
+			return nil, org, fmt.Errorf("unable to get connection %s: %v", key, err)
+		}
+
+		merakiClient, err := connectionClient(ctx, r.APIReader, &conn, r.Region, r.limiters.get(&conn), r.APIOptions...)
+		if err != nil {
+			return nil, org, fmt.Errorf("connection %s: %v", key, err)
+		}
+
+		if org.ID == "" && org.Name == "" {
+			org = conn.Spec.Organization
+		}
+		return merakiClient, org, nil
+	}
+
+	apiKey := r.APIKey
+	if cluster, ok := source.(*dnsv1alpha1.ClusterMerakiSource); ok && cluster.Spec.APIKeySecretRef != nil {
+		key, err := secretValue(ctx, r.APIReader, r.Namespace, cluster.Spec.APIKeySecretRef)
+		if err != nil {
+			return nil, org, err
+		}
+		apiKey = key
+	}
+
+	if apiKey == "" {
+		return nil, org, errors.New("a Meraki API key is required")
+	}
+
+	region, err := regionOption(spec.Region, r.Region)
+	if err != nil {
+		return nil, org, err
+	}
+	opts := append(append([]func(*meraki.Api){}, r.APIOptions...), region)
+	return meraki.New(apiKey, opts...), org, nil
+}
+
+// requeueInterval returns the time until the source should be synced again.
+// The interval is jittered so sources created at the same time, or requeued
+// after a restart, do not all query Meraki at once
+func (r *MerakiSourceReconciler) requeueInterval(spec *dnsv1alpha1.MerakiSourceSpec) time.Duration {
+	interval := r.RequeueInterval
+	if spec.Interval != nil {
+		interval = spec.Interval.Duration
+	}
+	return wait.Jitter(interval, requeueJitter)
+}
+
+// throttleInterval returns the minimum time between Meraki API queries for
+// the source
+func (r *MerakiSourceReconciler) throttleInterval(spec *dnsv1alpha1.MerakiSourceSpec) time.Duration {
+	if spec.MinSyncInterval != nil {
+		return spec.MinSyncInterval.Duration
+	}
+	return r.APIThrottleInterval
+}
+
+// metadataRecordsBlocked returns a reason if the metadata records of the
+// source can't be told apart from the external-dns TXT registry records
+func (r *MerakiSourceReconciler) metadataRecordsBlocked(spec *dnsv1alpha1.MerakiSourceSpec) string {
+	records := spec.MetadataRecords
+	if records == nil {
+		return ""
+	}
+	if r.TXTPrefix == "" {
+		return "metadata records require the controller --txt-prefix to be set to the external-dns TXT registry prefix"
+	}
+	if metadataPrefix(records) == r.TXTPrefix {
+		return fmt.Sprintf("metadata record prefix %q collides with the external-dns TXT registry prefix", r.TXTPrefix)
+	}
+	return ""
+}
+
+// deletionBlocked returns a reason if replacing the current records with the
+// desired records removes more records than the source allows
+func deletionBlocked(spec *dnsv1alpha1.MerakiSourceSpec, current, desired []*endpoint.Endpoint) string {
+	if len(current) == 0 {
+		return ""
+	}
+
+	if spec.MinEndpoints != nil && len(desired) < len(current) && len(desired) < int(*spec.MinEndpoints) {
+		return fmt.Sprintf("sync would leave %d records, fewer than the minimum of %d", len(desired), *spec.MinEndpoints)
+	}
+
+	if spec.MaxDeletionPercent != nil {
+		keep := map[string]bool{}
+		for _, e := range desired {
+			keep[endpointKey(e)] = true
+		}
+		removed := 0
+		for _, e := range current {
+			if !keep[endpointKey(e)] {
+				removed++
+			}
+		}
+		if removed*100 > int(*spec.MaxDeletionPercent)*len(current) {
+			return fmt.Sprintf("sync would remove %d of %d records, more than %d%%", removed, len(current), *spec.MaxDeletionPercent)
+		}
+	}
+
+	return ""
+}
+
+// syncRequested returns the sync request annotation and whether it has not
+// been handled yet
+func syncRequested(source Source) (string, bool) {
+	request := source.GetAnnotations()[dnsv1alpha1.SyncRequestedAnnotation]
+	return request, request != "" && request != source.GetSourceStatus().LastHandledSyncRequest
+}
+
+func allowDeletion(source Source) bool {
+	_, ok := source.GetAnnotations()[dnsv1alpha1.AllowDeletionAnnotation]
+	return ok
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, s string) []string {
+	var result []string
+	for _, v := range values {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
 }
 
 func (r *MerakiSourceReconciler) isNew(e endpoint.DNSEndpoint) bool {
@@ -0,0 +1,214 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// testEndpoints returns an A record for each name
+func testEndpoints(names ...string) []*endpoint.Endpoint {
+	var endpoints []*endpoint.Endpoint
+	for _, name := range names {
+		endpoints = append(endpoints, endpoint.NewEndpoint(name+".office.example.com", endpoint.RecordTypeA, "10.0.0.1"))
+	}
+	return endpoints
+}
+
+func TestDeletionBlocked(t *testing.T) {
+	three := int32(3)
+	twentyFive := int32(25)
+	fifty := int32(50)
+
+	tests := []struct {
+		name    string
+		spec    dnsv1alpha1.MerakiSourceSpec
+		current []*endpoint.Endpoint
+		desired []*endpoint.Endpoint
+		blocked bool
+	}{
+		{
+			name:    "no limits",
+			current: testEndpoints("a", "b", "c", "d"),
+		},
+		{
+			name:    "empty current records",
+			spec:    dnsv1alpha1.MerakiSourceSpec{MinEndpoints: &three, MaxDeletionPercent: &twentyFive},
+			desired: testEndpoints("a"),
+		},
+		{
+			name:    "removals within the percentage",
+			spec:    dnsv1alpha1.MerakiSourceSpec{MaxDeletionPercent: &fifty},
+			current: testEndpoints("a", "b", "c", "d"),
+			desired: testEndpoints("a", "b"),
+		},
+		{
+			name:    "removals over the percentage",
+			spec:    dnsv1alpha1.MerakiSourceSpec{MaxDeletionPercent: &twentyFive},
+			current: testEndpoints("a", "b", "c", "d"),
+			desired: testEndpoints("a", "b"),
+			blocked: true,
+		},
+		{
+			name:    "renamed records count as removed",
+			spec:    dnsv1alpha1.MerakiSourceSpec{MaxDeletionPercent: &fifty},
+			current: testEndpoints("a", "b"),
+			desired: testEndpoints("c", "d"),
+			blocked: true,
+		},
+		{
+			name:    "all records removed",
+			spec:    dnsv1alpha1.MerakiSourceSpec{MaxDeletionPercent: &fifty},
+			current: testEndpoints("a", "b"),
+			blocked: true,
+		},
+		{
+			name:    "shrinking to the minimum",
+			spec:    dnsv1alpha1.MerakiSourceSpec{MinEndpoints: &three},
+			current: testEndpoints("a", "b", "c", "d"),
+			desired: testEndpoints("a", "b", "c"),
+		},
+		{
+			name:    "shrinking below the minimum",
+			spec:    dnsv1alpha1.MerakiSourceSpec{MinEndpoints: &three},
+			current: testEndpoints("a", "b", "c", "d"),
+			desired: testEndpoints("a", "b"),
+			blocked: true,
+		},
+		{
+			name:    "growing below the minimum",
+			spec:    dnsv1alpha1.MerakiSourceSpec{MinEndpoints: &three},
+			current: testEndpoints("a"),
+			desired: testEndpoints("a", "b"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := deletionBlocked(&tt.spec, tt.current, tt.desired)
+			if blocked := msg != ""; blocked != tt.blocked {
+				t.Errorf("got blocked %v (%q), want %v", blocked, msg, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestAllowDeletionAppliesToOneSync(t *testing.T) {
+	metav1.AddToGroupVersion(scheme.Scheme, dnsv1alpha1.DNSEndpointGroupVersion)
+	scheme.Scheme.AddKnownTypes(dnsv1alpha1.DNSEndpointGroupVersion, &endpoint.DNSEndpoint{}, &endpoint.DNSEndpointList{})
+	if err := dnsv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	fifty := int32(50)
+	now := metav1.Now()
+	source := &dnsv1alpha1.MerakiSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "office",
+			Namespace:   "default",
+			UID:         "office-uid",
+			Finalizers:  []string{dnsv1alpha1.Finalizer},
+			Annotations: map[string]string{dnsv1alpha1.AllowDeletionAnnotation: "true"},
+		},
+		Spec: dnsv1alpha1.MerakiSourceSpec{
+			Organization:       dnsv1alpha1.MerakiRef{ID: "1"},
+			Network:            dnsv1alpha1.MerakiRef{ID: "N_1"},
+			Domain:             "office.example.com",
+			MaxDeletionPercent: &fifty,
+		},
+		Status: dnsv1alpha1.MerakiSourceStatus{SyncedAt: &now},
+	}
+	dnsEndpoint := &endpoint.DNSEndpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "office",
+			Namespace:         "default",
+			CreationTimestamp: now,
+		},
+		Spec: endpoint.DNSEndpointSpec{Endpoints: testEndpoints("a", "b", "c", "d")},
+	}
+
+	r := &MerakiSourceReconciler{
+		Client:              fake.NewFakeClientWithScheme(scheme.Scheme, source, dnsEndpoint),
+		Log:                 ctrl.Log,
+		Scheme:              scheme.Scheme,
+		APIThrottleInterval: time.Hour,
+		RequeueInterval:     time.Hour,
+	}
+	key := types.NamespacedName{Namespace: "default", Name: "office"}
+	ctx := context.Background()
+
+	// sync renders the cached clients, so meraki is never queried
+	sync := func(clients ...*meraki.Client) *dnsv1alpha1.MerakiSource {
+		t.Helper()
+		r.snapshots.set(key, &Snapshot{
+			Mode:         dnsv1alpha1.SourceModeClients,
+			Organization: source.Spec.Organization,
+			Network:      source.Spec.Network,
+			Clients:      clients,
+		})
+		var current dnsv1alpha1.MerakiSource
+		if err := r.Get(ctx, key, &current); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.reconcileSource(ctx, ctrl.Log, &current); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Get(ctx, key, &current); err != nil {
+			t.Fatal(err)
+		}
+		return &current
+	}
+	records := func() int {
+		t.Helper()
+		var current endpoint.DNSEndpoint
+		if err := r.Get(ctx, key, &current); err != nil {
+			t.Fatal(err)
+		}
+		return len(current.Spec.Endpoints)
+	}
+
+	// the acknowledged sync removes three of four records
+	synced := sync(&meraki.Client{ID: "a", Mac: "00:00:00:00:00:01", Description: "a", IP: "10.0.0.1"})
+	if got := records(); got != 1 {
+		t.Fatalf("got %d records after the acknowledged sync, want 1", got)
+	}
+	if allowDeletion(synced) {
+		t.Errorf("allow deletion annotation was not removed after the applied sync")
+	}
+
+	// the next mass deletion is blocked again
+	synced = sync()
+	if got := records(); got != 1 {
+		t.Errorf("got %d records after the unacknowledged sync, want 1", got)
+	}
+	condition := synced.Status.GetCondition(dnsv1alpha1.ConditionDeletionBlocked)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		t.Errorf("got deletion blocked condition %+v, want true", condition)
+	}
+}
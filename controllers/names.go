@@ -0,0 +1,98 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// defaultNameSources name clients after their description, falling back to
+// their MAC address
+var defaultNameSources = []dnsv1alpha1.NameSource{dnsv1alpha1.NameSourceDescription, dnsv1alpha1.NameSourceMAC}
+
+// invalidLabelChars are replaced when turning a free form name into a DNS
+// label
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+func nameSources(spec *dnsv1alpha1.MerakiSourceSpec) []dnsv1alpha1.NameSource {
+	if len(spec.NameSources) == 0 {
+		return defaultNameSources
+	}
+	return spec.NameSources
+}
+
+// usesSMNames returns true if clients may be named after their Systems
+// Manager device. Devices are only fetched in Clients mode
+func usesSMNames(spec *dnsv1alpha1.MerakiSourceSpec) bool {
+	if sourceMode(spec) != dnsv1alpha1.SourceModeClients {
+		return false
+	}
+	for _, source := range nameSources(spec) {
+		if source == dnsv1alpha1.NameSourceSM {
+			return true
+		}
+	}
+	return false
+}
+
+// smDeviceNames returns the names of the Systems Manager devices by lower case
+// Wi-Fi MAC address
+func smDeviceNames(devices []*meraki.SMDevice) map[string]string {
+	names := map[string]string{}
+	for _, device := range devices {
+		if device.WifiMac == "" || device.Name == "" {
+			continue
+		}
+		names[strings.ToLower(device.WifiMac)] = device.Name
+	}
+	return names
+}
+
+// dnsLabel turns a free form name such as "Jane's iPhone" into a DNS label
+func dnsLabel(name string) string {
+	name = invalidLabelChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
+// baseName returns the name of the client from the first name source that
+// has one, before any override is applied
+func baseName(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot, client *meraki.Client) string {
+	mac := strings.Replace(strings.ToLower(client.Mac), ":", "-", -1)
+	for _, source := range nameSources(spec) {
+		var name string
+		switch source {
+		case dnsv1alpha1.NameSourceSM:
+			name = dnsLabel(snapshot.SMNames[strings.ToLower(client.Mac)])
+		case dnsv1alpha1.NameSourceDescription:
+			if client.Description != "" {
+				name = client.DNSName()
+			}
+		case dnsv1alpha1.NameSourceUser:
+			name = dnsLabel(strings.SplitN(client.User, "@", 2)[0])
+		case dnsv1alpha1.NameSourceMAC:
+			name = mac
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return mac
+}
@@ -0,0 +1,198 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+	"golang.org/x/time/rate"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// DefaultPolicyCacheTTL is how long client policies are cached if the
+// reconciler does not set PolicyCacheTTL
+const DefaultPolicyCacheTTL = 30 * time.Minute
+
+// policyLookupRate limits the client policy requests per second across all
+// sources, so a large network doesn't run into the Meraki API rate limit when
+// its policies are not cached
+const policyLookupRate = 5
+
+// hasGroupPolicyFilter returns true if the source filters clients by policy.
+// Policies are only fetched in Clients mode
+func hasGroupPolicyFilter(spec *dnsv1alpha1.MerakiSourceSpec) bool {
+	return spec.Filter != nil && spec.Filter.GroupPolicies != nil && sourceMode(spec) == dnsv1alpha1.SourceModeClients
+}
+
+// policyName returns the group policy name for a client policy, or the policy
+// type for clients without a group policy
+func policyName(policy *meraki.ClientPolicy, groupPolicies map[string]string) string {
+	if policy.Type != meraki.ClientPolicyTypeGroupPolicy {
+		return policy.Type
+	}
+	if name, ok := groupPolicies[policy.GroupPolicyID.String()]; ok {
+		return name
+	}
+	return policy.GroupPolicyID.String()
+}
+
+// fetchPolicies returns the policy name of each client by client ID. Client
+// policies are looked up one request per client, so they are cached across
+// syncs and the requests are rate limited. Clients whose policy can't be
+// looked up are left out of the result
+func (r *MerakiSourceReconciler) fetchPolicies(ctx context.Context, log logr.Logger, merakiClient *meraki.Api, networkID string, clients []*meraki.Client) (map[string]string, error) {
+	groupPolicies, err := merakiClient.GroupPolicies(networkID)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, p := range groupPolicies {
+		names[p.GroupPolicyID.String()] = p.Name
+	}
+
+	ttl := r.PolicyCacheTTL
+	if ttl == 0 {
+		ttl = DefaultPolicyCacheTTL
+	}
+
+	clientIDs := map[string]bool{}
+	for _, client := range clients {
+		clientIDs[client.ID] = true
+	}
+	r.policies.prune(networkID, clientIDs, ttl)
+
+	policies := map[string]string{}
+	failed := 0
+	for _, client := range clients {
+		policy := r.policies.get(networkID, client.ID, ttl)
+		if policy == nil {
+			if err := r.policies.wait(ctx); err != nil {
+				return nil, err
+			}
+			policy, err = merakiClient.ClientPolicy(networkID, client.ID)
+			if err != nil {
+				log.V(1).Info("unable to look up client policy", "client", client.Mac, "error", err.Error())
+				failed++
+				continue
+			}
+			r.policies.set(networkID, client.ID, policy)
+		}
+		policies[client.ID] = policyName(policy, names)
+	}
+	if failed > 0 {
+		log.Info("skipped clients whose policy could not be looked up", "clients", failed)
+	}
+	return policies, nil
+}
+
+// filterPolicies returns a copy of the snapshot with only the clients whose
+// policy passes the group policy filter of the source. The cached snapshot is
+// not modified
+func filterPolicies(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot) *Snapshot {
+	if !hasGroupPolicyFilter(spec) {
+		return snapshot
+	}
+	filter := spec.Filter.GroupPolicies
+
+	filtered := snapshot.filtered()
+	for _, client := range snapshot.Clients {
+		policy, ok := snapshot.Policies[client.ID]
+		if !ok {
+			// the policy lookup failed, so the filter can't be applied
+			continue
+		}
+		if len(filter.Include) > 0 && !containsFold(filter.Include, policy) {
+			continue
+		}
+		if containsFold(filter.Exclude, policy) {
+			continue
+		}
+		filtered.Clients = append(filtered.Clients, client)
+	}
+	return filtered
+}
+
+type cachedPolicy struct {
+	policy    *meraki.ClientPolicy
+	fetchedAt time.Time
+}
+
+// policyCache holds the policy of each client in memory to limit the number
+// of API requests
+type policyCache struct {
+	mu       sync.Mutex
+	policies map[string]cachedPolicy
+	limiter  *rate.Limiter
+}
+
+func policyKey(networkID, clientID string) string {
+	return networkID + "/" + clientID
+}
+
+// get returns the cached policy of the client, or nil if it is not cached or
+// older than ttl
+func (c *policyCache) get(networkID, clientID string, ttl time.Duration) *meraki.ClientPolicy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.policies[policyKey(networkID, clientID)]
+	if !ok || time.Since(cached.fetchedAt) > ttl {
+		return nil
+	}
+	return cached.policy
+}
+
+func (c *policyCache) set(networkID, clientID string, policy *meraki.ClientPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.policies == nil {
+		c.policies = map[string]cachedPolicy{}
+	}
+	c.policies[policyKey(networkID, clientID)] = cachedPolicy{policy: policy, fetchedAt: time.Now()}
+}
+
+// prune removes the expired policies, and the policies of clients that are no
+// longer in the network
+func (c *policyCache) prune(networkID string, clientIDs map[string]bool, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prefix := policyKey(networkID, "")
+	for key, cached := range c.policies {
+		if time.Since(cached.fetchedAt) > ttl {
+			delete(c.policies, key)
+			continue
+		}
+		if strings.HasPrefix(key, prefix) && !clientIDs[strings.TrimPrefix(key, prefix)] {
+			delete(c.policies, key)
+		}
+	}
+}
+
+// wait blocks until the next client policy may be requested
+func (c *policyCache) wait(ctx context.Context) error {
+	c.mu.Lock()
+	if c.limiter == nil {
+		c.limiter = rate.NewLimiter(policyLookupRate, 1)
+	}
+	limiter := c.limiter
+	c.mu.Unlock()
+	return limiter.Wait(ctx)
+}
@@ -0,0 +1,143 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// portForwardingEndpoints returns an SRV record for each port forwarding rule,
+// e.g. _https._tcp.example.com with the target 0 0 443 web.example.com. The
+// target is the client with the LAN IP of the rule. Rules for addresses
+// without a client get an A record named after the service. Rules for clients
+// without their own record, and for addresses that may not be published, are
+// skipped
+func portForwardingEndpoints(spec *dnsv1alpha1.MerakiSourceSpec, groups []*clientMatcher, snapshot *Snapshot) ([]*endpoint.Endpoint, error) {
+	f, err := newAddressFilter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := spec.TTL
+	if spec.PortForwarding.TTL != nil {
+		ttl = spec.PortForwarding.TTL
+	}
+
+	var endpoints []*endpoint.Endpoint
+	srv := map[string]*endpoint.Endpoint{}
+	hosts := map[string]bool{}
+	for _, rule := range snapshot.PortForwardingRules {
+		service := serviceLabel(spec.PortForwarding, rule)
+		port := firstPort(rule.LocalPort)
+		if service == "" || port == 0 || f.check(rule.LanIP) != "" {
+			continue
+		}
+
+		host := ""
+		for _, client := range snapshot.Clients {
+			if client.IP == rule.LanIP {
+				if name, ok := clientName(spec, snapshot, client); ok && !groupMember(spec, groups, client) {
+					domain, _ := clientDomain(spec, client)
+					host = name + "." + domain
+				}
+				break
+			}
+		}
+		if host == "" && hasClientWithIP(snapshot.allClients(), rule.LanIP) {
+			// the client was deliberately left out of the records
+			continue
+		}
+		if host == "" {
+			host = service + "." + spec.Domain
+			if !hosts[host] {
+				hosts[host] = true
+				endpoints = append(endpoints, endpoint.NewEndpoint(host, endpoint.RecordTypeA, rule.LanIP))
+			}
+		}
+
+		for _, proto := range ruleProtocols(rule) {
+			name := fmt.Sprintf("_%s._%s.%s", service, proto, spec.Domain)
+			target := fmt.Sprintf("0 0 %d %s", port, host)
+			if e, ok := srv[name]; ok {
+				e.Targets = append(e.Targets, target)
+				continue
+			}
+			e := endpoint.NewEndpoint(name, endpoint.RecordTypeSRV, target)
+			srv[name] = e
+			endpoints = append(endpoints, e)
+		}
+	}
+
+	for _, e := range endpoints {
+		if ttl != nil {
+			e.RecordTTL = endpoint.TTL(*ttl)
+		}
+	}
+	if err := setEndpointOptions(spec, nil, nil, endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func hasClientWithIP(clients []*meraki.Client, ip string) bool {
+	for _, client := range clients {
+		if client.IP == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceLabel returns the service label for a rule from the service mappings,
+// or from the rule name
+func serviceLabel(pf *dnsv1alpha1.PortForwarding, rule *meraki.PortForwardingRule) string {
+	for _, m := range pf.Services {
+		if strings.EqualFold(m.Rule, rule.Name) {
+			return m.Service
+		}
+	}
+	return dnsLabel(rule.Name)
+}
+
+// ruleProtocols returns the SRV protocol labels for a rule
+func ruleProtocols(rule *meraki.PortForwardingRule) []string {
+	switch strings.ToLower(rule.Protocol) {
+	case "tcp":
+		return []string{"tcp"}
+	case "udp":
+		return []string{"udp"}
+	case "any":
+		return []string{"tcp", "udp"}
+	}
+	return nil
+}
+
+// firstPort returns the first port of a port or port range such as 8080-8081,
+// or 0 if it is not valid
+func firstPort(ports string) int {
+	port, err := strconv.Atoi(strings.TrimSpace(strings.SplitN(ports, "-", 2)[0]))
+	if err != nil || port < 1 || port > 65535 {
+		return 0
+	}
+	return port
+}
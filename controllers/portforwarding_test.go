@@ -0,0 +1,163 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// records formats endpoints as sorted "name type targets" lines for
+// comparison
+func records(endpoints []*endpoint.Endpoint) []string {
+	var lines []string
+	for _, e := range endpoints {
+		lines = append(lines, e.DNSName+" "+e.RecordType+" "+strings.Join(e.Targets, ","))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func equalRecords(t *testing.T, got []*endpoint.Endpoint, want []string) {
+	t.Helper()
+	lines := records(got)
+	sort.Strings(want)
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got records:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPortForwardingEndpoints(t *testing.T) {
+	vlan20 := int32(20)
+	rules := []*meraki.PortForwardingRule{
+		{Name: "ssh", LanIP: "10.0.0.6", Protocol: "tcp", LocalPort: "22"},
+	}
+
+	tests := []struct {
+		name    string
+		spec    dnsv1alpha1.MerakiSourceSpec
+		clients []*meraki.Client
+		want    []string
+	}{
+		{
+			name:    "client target",
+			clients: []*meraki.Client{{ID: "a", Mac: "00:00:00:00:00:06", Description: "web", IP: "10.0.0.6"}},
+			want:    []string{"_ssh._tcp.office.example.com SRV 0 0 22 web.office.example.com"},
+		},
+		{
+			name: "no client",
+			want: []string{
+				"ssh.office.example.com A 10.0.0.6",
+				"_ssh._tcp.office.example.com SRV 0 0 22 ssh.office.example.com",
+			},
+		},
+		{
+			name: "excluded client",
+			spec: dnsv1alpha1.MerakiSourceSpec{
+				Overrides: []dnsv1alpha1.ClientOverride{{MAC: "00:00:00:00:00:06", Exclude: true}},
+			},
+			clients: []*meraki.Client{{ID: "a", Mac: "00:00:00:00:00:06", Description: "web", IP: "10.0.0.6"}},
+		},
+		{
+			name:    "filtered client",
+			spec:    dnsv1alpha1.MerakiSourceSpec{ExcludedCIDRs: []string{"10.0.0.0/29"}},
+			clients: []*meraki.Client{{ID: "a", Mac: "00:00:00:00:00:06", Description: "web", IP: "10.0.0.6"}},
+		},
+		{
+			name: "client in a subdomain",
+			spec: dnsv1alpha1.MerakiSourceSpec{
+				Subdomains: &dnsv1alpha1.Subdomains{
+					Rules: []dnsv1alpha1.SubdomainRule{{VLAN: &vlan20, Label: "vlan20"}},
+				},
+			},
+			clients: []*meraki.Client{{ID: "a", Mac: "00:00:00:00:00:06", Description: "web", IP: "10.0.0.6", Vlan: 20}},
+			want:    []string{"_ssh._tcp.office.example.com SRV 0 0 22 web.vlan20.office.example.com"},
+		},
+		{
+			name: "group member without its own record",
+			spec: dnsv1alpha1.MerakiSourceSpec{
+				Groups: []dnsv1alpha1.Group{{
+					Name:           "servers",
+					Selector:       dnsv1alpha1.ClientSelector{MACs: []string{"00:00:00:00:00:06"}},
+					ExcludeMembers: true,
+				}},
+			},
+			clients: []*meraki.Client{{ID: "a", Mac: "00:00:00:00:00:06", Description: "web", IP: "10.0.0.6"}},
+		},
+		{
+			name: "excluded address without a client",
+			spec: dnsv1alpha1.MerakiSourceSpec{AllowedCIDRs: []string{"192.168.0.0/16"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.spec
+			spec.Domain = "office.example.com"
+			spec.PortForwarding = &dnsv1alpha1.PortForwarding{}
+
+			snapshot, _, err := filterClients(&spec, &Snapshot{Clients: tt.clients, PortForwardingRules: rules})
+			if err != nil {
+				t.Fatal(err)
+			}
+			groups, err := groupMatchers(&spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			endpoints, err := portForwardingEndpoints(&spec, groups, snapshot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			equalRecords(t, endpoints, tt.want)
+		})
+	}
+}
+
+func TestPortForwardingEndpointOptions(t *testing.T) {
+	spec := &dnsv1alpha1.MerakiSourceSpec{
+		Domain:         "office.example.com",
+		PortForwarding: &dnsv1alpha1.PortForwarding{},
+		EndpointLabels: map[string]string{"team": "net", "vlan": "{{ .Vlan }}"},
+		ProviderSpecific: []dnsv1alpha1.ProviderSpecificProperty{
+			{Name: "external-dns.alpha.kubernetes.io/cloudflare-proxied", Value: "false"},
+		},
+	}
+	snapshot := &Snapshot{PortForwardingRules: []*meraki.PortForwardingRule{
+		{Name: "ssh", LanIP: "10.0.0.6", Protocol: "tcp", LocalPort: "22"},
+	}}
+
+	endpoints, err := portForwardingEndpoints(spec, nil, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range endpoints {
+		if e.Labels["team"] != "net" {
+			t.Errorf("%s: expected the team label, got %v", e.DNSName, e.Labels)
+		}
+		if _, ok := e.Labels["vlan"]; ok {
+			t.Errorf("%s: expected the templated label to be left out", e.DNSName)
+		}
+		if len(e.ProviderSpecific) != 1 {
+			t.Errorf("%s: expected the provider specific property, got %v", e.DNSName, e.ProviderSpecific)
+		}
+	}
+}
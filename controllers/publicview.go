@@ -0,0 +1,86 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"k8s.io/apimachinery/pkg/types"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// publicEndpointName returns the name of the DNSEndpoint with the public view
+// records of a source
+func publicEndpointName(source Source) types.NamespacedName {
+	return types.NamespacedName{Namespace: source.GetTargetNamespace(), Name: source.GetName() + "-public"}
+}
+
+// publicEndpoints returns a record in the public domain for each client with
+// a 1:1 NAT rule, pointing at the public IP of the rule
+func publicEndpoints(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot) ([]*endpoint.Endpoint, error) {
+	view := spec.PublicView
+	ttl := spec.TTL
+	if view.TTL != nil {
+		ttl = view.TTL
+	}
+
+	var endpoints []*endpoint.Endpoint
+	for _, rule := range snapshot.NATRules {
+		if rule.LanIP == "" || rule.PublicIP == "" {
+			continue
+		}
+		for _, client := range snapshot.Clients {
+			if client.IP != rule.LanIP {
+				continue
+			}
+			name, ok := clientName(spec, snapshot, client)
+			if !ok {
+				continue
+			}
+			e := endpoint.NewEndpoint(name+"."+view.Domain, endpoint.RecordTypeA, rule.PublicIP)
+			if ttl != nil {
+				e.RecordTTL = endpoint.TTL(*ttl)
+			}
+			if err := setEndpointOptions(spec, clientOverride(spec, client), client, []*endpoint.Endpoint{e}); err != nil {
+				return nil, fmt.Errorf("client %s: %v", client.Mac, err)
+			}
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints, nil
+}
+
+// publicViewEndpoint returns the DNSEndpoint of the public view records of the
+// source. The view is nil when the source no longer has a public view, so the
+// DNSEndpoint is deleted
+func (r *MerakiSourceReconciler) publicViewEndpoint(ctx context.Context, log logr.Logger, source Source, spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot) (*viewEndpoint, error) {
+	var view *endpointView
+	if hasPublicView(spec) {
+		endpoints, err := publicEndpoints(spec, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		view = &endpointView{
+			labels:    spec.PublicView.Labels,
+			endpoints: endpoints,
+		}
+	}
+	return r.getViewEndpoint(ctx, log, publicEndpointName(source), view)
+}
@@ -0,0 +1,43 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// secretValue reads a key from a Secret. Secrets are read directly from the
+// API server so the controller does not need to cache every Secret in the
+// cluster
+func secretValue(ctx context.Context, reader client.Reader, namespace string, selector *corev1.SecretKeySelector) (string, error) {
+	var secret corev1.Secret
+	key := types.NamespacedName{Namespace: namespace, Name: selector.Name}
+	if err := reader.Get(ctx, key, &secret); err != nil {
+		return "", fmt.Errorf("unable to get secret %s: %v", key, err)
+	}
+
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no %s key", key, selector.Key)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
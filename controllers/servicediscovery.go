@@ -0,0 +1,117 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// recordTypePTR is not defined by the external-dns version we build against
+const recordTypePTR = "PTR"
+
+// serviceDiscoveryEndpoints returns the RFC 6763 DNS-SD records for the
+// services of matching clients:
+//
+//	_services._dns-sd._udp.<domain> PTR _ipp._tcp.<domain>
+//	_ipp._tcp.<domain>              PTR printer._ipp._tcp.<domain>
+//	printer._ipp._tcp.<domain>      SRV 0 0 631 printer.<domain>
+//	printer._ipp._tcp.<domain>      TXT ...
+//
+// Services are advertised in the domain of the client record, so clients with
+// the same name in different subdomains don't collide
+func serviceDiscoveryEndpoints(spec *dnsv1alpha1.MerakiSourceSpec, groups []*clientMatcher, snapshot *Snapshot) ([]*endpoint.Endpoint, error) {
+	var endpoints []*endpoint.Endpoint
+	pointers := map[string]*endpoint.Endpoint{}
+	pointer := func(name, target string) {
+		ptr, ok := pointers[name]
+		if !ok {
+			ptr = endpoint.NewEndpoint(name, recordTypePTR)
+			pointers[name] = ptr
+			endpoints = append(endpoints, ptr)
+		}
+		for _, t := range ptr.Targets {
+			if t == target {
+				return
+			}
+		}
+		ptr.Targets = append(ptr.Targets, target)
+	}
+	instances := map[string]bool{}
+
+	for i := range spec.ServiceDiscovery {
+		rule := &spec.ServiceDiscovery[i]
+		m, err := newClientMatcher(&rule.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("service discovery %s: %v", rule.Service, err)
+		}
+
+		protocol := rule.Protocol
+		if protocol == "" {
+			protocol = dnsv1alpha1.ServiceProtocolTCP
+		}
+
+		for _, client := range snapshot.Clients {
+			name, ok := clientName(spec, snapshot, client)
+			if !ok || client.IP == "" || groupMember(spec, groups, client) || !m.matches(client) {
+				continue
+			}
+
+			domain, _ := clientDomain(spec, client)
+			serviceType := fmt.Sprintf("_%s._%s.%s", rule.Service, protocol, domain)
+			instance := name + "." + serviceType
+			if instances[instance] {
+				continue
+			}
+			instances[instance] = true
+
+			pointer("_services._dns-sd._udp."+domain, serviceType)
+			pointer(serviceType, instance)
+
+			txt := rule.TXT
+			if len(txt) == 0 {
+				// every service must have a TXT record, even if it is empty
+				txt = []string{""}
+			}
+			records := []*endpoint.Endpoint{
+				endpoint.NewEndpoint(instance, endpoint.RecordTypeSRV, fmt.Sprintf("0 0 %d %s.%s", rule.Port, name, domain)),
+				endpoint.NewEndpoint(instance, endpoint.RecordTypeTXT, txt...),
+			}
+			if err := setEndpointOptions(spec, clientOverride(spec, client), client, records); err != nil {
+				return nil, fmt.Errorf("service discovery %s: client %s: %v", rule.Service, client.Mac, err)
+			}
+			endpoints = append(endpoints, records...)
+		}
+	}
+
+	var ptrs []*endpoint.Endpoint
+	for _, e := range endpoints {
+		if spec.TTL != nil {
+			e.RecordTTL = endpoint.TTL(*spec.TTL)
+		}
+		if e.RecordType == recordTypePTR {
+			ptrs = append(ptrs, e)
+		}
+	}
+	// the browse and service type pointers are shared by clients
+	if err := setEndpointOptions(spec, nil, nil, ptrs); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
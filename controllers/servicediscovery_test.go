@@ -0,0 +1,59 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+func TestServiceDiscoveryEndpoints(t *testing.T) {
+	vlan20 := int32(20)
+	spec := &dnsv1alpha1.MerakiSourceSpec{
+		Domain: "office.example.com",
+		Subdomains: &dnsv1alpha1.Subdomains{
+			Rules: []dnsv1alpha1.SubdomainRule{{VLAN: &vlan20, Label: "lab"}},
+		},
+		ServiceDiscovery: []dnsv1alpha1.ServiceDiscoveryRule{{
+			Service:  "ipp",
+			Port:     631,
+			Selector: dnsv1alpha1.ClientSelector{Manufacturers: []string{"Brother"}},
+			TXT:      []string{"rp=ipp/print"},
+		}},
+	}
+	snapshot := &Snapshot{Clients: []*meraki.Client{
+		{ID: "a", Mac: "00:00:00:00:00:01", Description: "printer", Manufacturer: "Brother", IP: "10.0.0.1", Vlan: 10},
+		{ID: "b", Mac: "00:00:00:00:00:02", Description: "printer", Manufacturer: "Brother", IP: "10.0.20.1", Vlan: 20},
+	}}
+
+	endpoints, err := serviceDiscoveryEndpoints(spec, nil, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalRecords(t, endpoints, []string{
+		"_services._dns-sd._udp.office.example.com PTR _ipp._tcp.office.example.com",
+		"_ipp._tcp.office.example.com PTR printer._ipp._tcp.office.example.com",
+		"printer._ipp._tcp.office.example.com SRV 0 0 631 printer.office.example.com",
+		"printer._ipp._tcp.office.example.com TXT rp=ipp/print",
+		"_services._dns-sd._udp.lab.office.example.com PTR _ipp._tcp.lab.office.example.com",
+		"_ipp._tcp.lab.office.example.com PTR printer._ipp._tcp.lab.office.example.com",
+		"printer._ipp._tcp.lab.office.example.com SRV 0 0 631 printer.lab.office.example.com",
+		"printer._ipp._tcp.lab.office.example.com TXT rp=ipp/print",
+	})
+}
@@ -0,0 +1,196 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+	"k8s.io/apimachinery/pkg/types"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// Snapshot is the data fetched from Meraki for a source. Records are rendered
+// from the snapshot so spec changes can be applied without querying Meraki
+type Snapshot struct {
+	// Connection, Region, Organization and Network are the references the
+	// snapshot was fetched for
+	Connection   string
+	Region       dnsv1alpha1.Region
+	Organization dnsv1alpha1.MerakiRef
+	Network      dnsv1alpha1.MerakiRef
+
+	// Mode is the source mode the snapshot was fetched for. Only the data
+	// needed for the mode is fetched
+	Mode dnsv1alpha1.SourceMode
+	// PublicView is true if NAT rules were fetched for a public view
+	PublicView bool
+	// PortForwarding is true if port forwarding rules were fetched
+	PortForwarding bool
+	// GroupPolicies is true if client policies were fetched
+	GroupPolicies bool
+	// Dashboard is true if the settings of the network were fetched
+	Dashboard bool
+	// SystemsManager is true if Systems Manager devices were fetched
+	SystemsManager bool
+
+	NetworkID string
+	Clients   []*meraki.Client
+	Devices   []*meraki.Device
+	// Uplinks are the uplinks of the appliances in Devices by serial
+	Uplinks  map[string][]*meraki.Uplink
+	NATRules []*meraki.OneToOneNatRule
+	// PortForwardingRules are the port forwarding rules of the appliance
+	PortForwardingRules []*meraki.PortForwardingRule
+	// Policies are the policy names of the clients by client ID
+	Policies map[string]string
+	// DashboardSettings are the dns- settings from the tags and notes of the
+	// network
+	DashboardSettings map[string]string
+	// SMNames are the names of the Systems Manager devices by lower case MAC
+	SMNames   map[string]string
+	FetchedAt time.Time
+
+	// unfiltered are the clients fetched from Meraki, before any filter
+	unfiltered []*meraki.Client
+}
+
+// filtered returns a copy of the snapshot without clients, for a filter to
+// add the clients it keeps. The cached snapshot is not modified
+func (s *Snapshot) filtered() *Snapshot {
+	filtered := *s
+	if filtered.unfiltered == nil {
+		filtered.unfiltered = s.Clients
+	}
+	filtered.Clients = nil
+	return &filtered
+}
+
+// allClients returns every client fetched from Meraki, including the clients
+// removed by filters
+func (s *Snapshot) allClients() []*meraki.Client {
+	if s.unfiltered != nil {
+		return s.unfiltered
+	}
+	return s.Clients
+}
+
+// matches returns true if the snapshot was fetched for the connection, region,
+// organization, network and mode in the spec, and has the NAT rules, port
+// forwarding rules, client policies, network settings and Systems Manager
+// devices the spec needs
+func (s *Snapshot) matches(spec *dnsv1alpha1.MerakiSourceSpec) bool {
+	return s != nil &&
+		s.Connection == connectionName(spec) &&
+		s.Region == spec.Region &&
+		s.Mode == sourceMode(spec) &&
+		(s.PublicView || !hasPublicView(spec)) &&
+		(s.PortForwarding || !hasPortForwarding(spec)) &&
+		(s.GroupPolicies || !hasGroupPolicyFilter(spec)) &&
+		(s.Dashboard || !hasDashboardConfig(spec)) &&
+		(s.SystemsManager || !usesSMNames(spec)) &&
+		s.Organization == spec.Organization &&
+		s.Network == spec.Network
+}
+
+func sourceMode(spec *dnsv1alpha1.MerakiSourceSpec) dnsv1alpha1.SourceMode {
+	if spec.Mode == "" {
+		return dnsv1alpha1.SourceModeClients
+	}
+	return spec.Mode
+}
+
+// hasPublicView returns true if the source publishes a public view. Public
+// views are only supported in Clients mode
+func hasPublicView(spec *dnsv1alpha1.MerakiSourceSpec) bool {
+	return spec.PublicView != nil && sourceMode(spec) == dnsv1alpha1.SourceModeClients
+}
+
+// hasPortForwarding returns true if the source publishes port forwarding
+// records. They are only supported in Clients mode
+func hasPortForwarding(spec *dnsv1alpha1.MerakiSourceSpec) bool {
+	return spec.PortForwarding != nil && sourceMode(spec) == dnsv1alpha1.SourceModeClients
+}
+
+func connectionName(spec *dnsv1alpha1.MerakiSourceSpec) string {
+	if spec.ConnectionRef == nil {
+		return ""
+	}
+	return spec.ConnectionRef.Name
+}
+
+// snapshotCache holds the last snapshot for each source in memory
+type snapshotCache struct {
+	mu        sync.Mutex
+	snapshots map[types.NamespacedName]*Snapshot
+}
+
+func (c *snapshotCache) get(key types.NamespacedName) *Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.snapshots[key]
+}
+
+func (c *snapshotCache) set(key types.NamespacedName, snapshot *Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.snapshots == nil {
+		c.snapshots = map[types.NamespacedName]*Snapshot{}
+	}
+	c.snapshots[key] = snapshot
+}
+
+func (c *snapshotCache) delete(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.snapshots, key)
+}
+
+// fetchDelays spreads the first query of each source after a restart, when
+// nothing is cached, so the sources don't all query Meraki at once
+type fetchDelays struct {
+	mu        sync.Mutex
+	deadlines map[types.NamespacedName]time.Time
+}
+
+// until returns how long the source has to wait before its first query. The
+// time is picked once per source, at random between earliest and earliest
+// plus spread
+func (c *fetchDelays) until(key types.NamespacedName, earliest time.Time, spread time.Duration) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deadlines == nil {
+		c.deadlines = map[types.NamespacedName]time.Time{}
+	}
+	deadline, ok := c.deadlines[key]
+	if !ok {
+		if now := time.Now(); earliest.Before(now) {
+			earliest = now
+		}
+		deadline = earliest.Add(time.Duration(rand.Int63n(int64(spread) + 1)))
+		c.deadlines[key] = deadline
+	}
+	return time.Until(deadline)
+}
+
+func (c *fetchDelays) delete(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.deadlines, key)
+}
@@ -0,0 +1,173 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"k8s.io/apimachinery/pkg/types"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// addressMapping translates addresses in one network to the same host address
+// in another network of the same size
+type addressMapping struct {
+	from *net.IPNet
+	to   *net.IPNet
+}
+
+func newAddressMappings(translation *dnsv1alpha1.AddressTranslation) ([]addressMapping, error) {
+	var mappings []addressMapping
+	for _, m := range translation.Mappings {
+		_, from, err := net.ParseCIDR(m.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address mapping: %v", err)
+		}
+		_, to, err := net.ParseCIDR(m.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address mapping: %v", err)
+		}
+		if from.IP.To4() == nil || to.IP.To4() == nil {
+			return nil, fmt.Errorf("address mapping %s to %s: only IPv4 is supported", m.From, m.To)
+		}
+		fromSize, _ := from.Mask.Size()
+		toSize, _ := to.Mask.Size()
+		if fromSize != toSize {
+			return nil, fmt.Errorf("address mapping %s to %s: networks must be the same size", m.From, m.To)
+		}
+		mappings = append(mappings, addressMapping{from: from, to: to})
+	}
+	return mappings, nil
+}
+
+// translateAddress returns the translated address, or the address as is if
+// no mapping applies
+func translateAddress(mappings []addressMapping, address string) string {
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return address
+	}
+	for _, m := range mappings {
+		if !m.from.Contains(ip) {
+			continue
+		}
+		network := m.to.IP.To4()
+		translated := make(net.IP, net.IPv4len)
+		for i := range translated {
+			translated[i] = network[i] | ip[i]&^m.to.Mask[i]
+		}
+		return translated.String()
+	}
+	return address
+}
+
+// translateEndpoints translates the targets of the A records in place
+func translateEndpoints(mappings []addressMapping, endpoints []*endpoint.Endpoint) {
+	for _, e := range endpoints {
+		if e.RecordType != endpoint.RecordTypeA {
+			continue
+		}
+		for i, target := range e.Targets {
+			e.Targets[i] = translateAddress(mappings, target)
+		}
+	}
+}
+
+// translatedEndpoints returns translated copies of the A records. If the
+// translation has a domain, the copies are moved from the source domain to it
+// and records outside the source domain are left out
+func translatedEndpoints(spec *dnsv1alpha1.MerakiSourceSpec, mappings []addressMapping, endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	domain := spec.AddressTranslation.Domain
+
+	var translated []*endpoint.Endpoint
+	for _, e := range endpoints {
+		if e.RecordType != endpoint.RecordTypeA {
+			continue
+		}
+
+		name := e.DNSName
+		if domain != "" {
+			if name != spec.Domain && !strings.HasSuffix(name, "."+spec.Domain) {
+				continue
+			}
+			name = strings.TrimSuffix(name, spec.Domain) + domain
+		}
+
+		var targets []string
+		for _, target := range e.Targets {
+			targets = append(targets, translateAddress(mappings, target))
+		}
+
+		c := endpoint.NewEndpointWithTTL(name, e.RecordType, e.RecordTTL, targets...)
+		for k, v := range e.Labels {
+			c.Labels[k] = v
+		}
+		c.ProviderSpecific = append(c.ProviderSpecific, e.ProviderSpecific...)
+		translated = append(translated, c)
+	}
+	return translated
+}
+
+// applyAddressTranslation translates the records of the source in place, or
+// adds translated copies in the translation domain. Translations published to
+// a separate DNSEndpoint are left to syncTranslatedEndpoint
+func applyAddressTranslation(spec *dnsv1alpha1.MerakiSourceSpec, endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
+	translation := spec.AddressTranslation
+	if translation == nil || translation.SeparateEndpoint {
+		return endpoints, nil
+	}
+
+	mappings, err := newAddressMappings(translation)
+	if err != nil {
+		return nil, err
+	}
+
+	if translation.Domain == "" {
+		translateEndpoints(mappings, endpoints)
+		return endpoints, nil
+	}
+	return append(endpoints, translatedEndpoints(spec, mappings, endpoints)...), nil
+}
+
+// translatedEndpointName returns the name of the DNSEndpoint with the
+// translated records of a source
+func translatedEndpointName(source Source) types.NamespacedName {
+	return types.NamespacedName{Namespace: source.GetTargetNamespace(), Name: source.GetName() + "-translated"}
+}
+
+// translatedViewEndpoint returns the DNSEndpoint of the translated copies of
+// the source records. The view is nil when the source no longer translates to
+// a separate endpoint, so the DNSEndpoint is deleted
+func (r *MerakiSourceReconciler) translatedViewEndpoint(ctx context.Context, log logr.Logger, source Source, spec *dnsv1alpha1.MerakiSourceSpec, endpoints []*endpoint.Endpoint) (*viewEndpoint, error) {
+	var view *endpointView
+	if translation := spec.AddressTranslation; translation != nil && translation.SeparateEndpoint {
+		mappings, err := newAddressMappings(translation)
+		if err != nil {
+			return nil, err
+		}
+		view = &endpointView{
+			labels:    translation.Labels,
+			endpoints: translatedEndpoints(spec, mappings, endpoints),
+		}
+	}
+	return r.getViewEndpoint(ctx, log, translatedEndpointName(source), view)
+}
@@ -0,0 +1,189 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+func TestNewAddressMappings(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		invalid bool
+	}{
+		{name: "same size", from: "192.168.128.0/24", to: "10.50.128.0/24"},
+		{name: "host bits in the CIDR", from: "192.168.128.7/24", to: "10.50.128.9/24"},
+		{name: "mismatched prefix sizes", from: "192.168.128.0/24", to: "10.50.0.0/16", invalid: true},
+		{name: "invalid from", from: "192.168.128.0", to: "10.50.128.0/24", invalid: true},
+		{name: "invalid to", from: "192.168.128.0/24", to: "10.50.128.0/33", invalid: true},
+		{name: "IPv6", from: "2001:db8::/64", to: "2001:db8:1::/64", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newAddressMappings(&dnsv1alpha1.AddressTranslation{
+				Mappings: []dnsv1alpha1.AddressMapping{{From: tt.from, To: tt.to}},
+			})
+			if tt.invalid && err == nil {
+				t.Errorf("expected mapping %s to %s to be invalid", tt.from, tt.to)
+			}
+			if !tt.invalid && err != nil {
+				t.Errorf("expected mapping %s to %s to be valid, got %v", tt.from, tt.to, err)
+			}
+		})
+	}
+}
+
+func TestTranslateAddress(t *testing.T) {
+	mappings, err := newAddressMappings(&dnsv1alpha1.AddressTranslation{
+		Mappings: []dnsv1alpha1.AddressMapping{
+			{From: "192.168.128.0/24", To: "10.50.128.0/24"},
+			{From: "172.16.0.0/12", To: "100.80.0.0/12"},
+			{From: "192.168.1.0/30", To: "10.0.0.4/30"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"192.168.128.5", "10.50.128.5"},
+		{"192.168.128.255", "10.50.128.255"},
+		{"172.31.255.1", "100.95.255.1"},
+		{"192.168.1.3", "10.0.0.7"},
+		{"192.168.1.4", "192.168.1.4"},
+		{"192.168.129.5", "192.168.129.5"},
+		{"2001:db8::1", "2001:db8::1"},
+		{"not-an-address", "not-an-address"},
+	}
+
+	for _, tt := range tests {
+		if got := translateAddress(mappings, tt.address); got != tt.want {
+			t.Errorf("translateAddress(%s) = %s, want %s", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestApplyAddressTranslation(t *testing.T) {
+	mappings := []dnsv1alpha1.AddressMapping{{From: "192.168.128.0/24", To: "10.50.128.0/24"}}
+
+	tests := []struct {
+		name        string
+		translation dnsv1alpha1.AddressTranslation
+		want        []string
+	}{
+		{
+			name:        "in place",
+			translation: dnsv1alpha1.AddressTranslation{Mappings: mappings},
+			want: []string{
+				"printer.branch.example.com A 10.50.128.5",
+				"nas.branch.example.com A 172.16.0.9",
+				"www.branch.example.com CNAME printer.branch.example.com",
+				"printer.other.example.com A 10.50.128.6",
+			},
+		},
+		{
+			// records outside the source domain are not copied
+			name:        "copies into a domain",
+			translation: dnsv1alpha1.AddressTranslation{Mappings: mappings, Domain: "branch.vpn.example.com"},
+			want: []string{
+				"printer.branch.example.com A 192.168.128.5",
+				"nas.branch.example.com A 172.16.0.9",
+				"www.branch.example.com CNAME printer.branch.example.com",
+				"printer.other.example.com A 192.168.128.6",
+				"printer.branch.vpn.example.com A 10.50.128.5",
+				"nas.branch.vpn.example.com A 172.16.0.9",
+			},
+		},
+		{
+			name:        "separate endpoint",
+			translation: dnsv1alpha1.AddressTranslation{Mappings: mappings, SeparateEndpoint: true},
+			want: []string{
+				"printer.branch.example.com A 192.168.128.5",
+				"nas.branch.example.com A 172.16.0.9",
+				"www.branch.example.com CNAME printer.branch.example.com",
+				"printer.other.example.com A 192.168.128.6",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translation := tt.translation
+			spec := &dnsv1alpha1.MerakiSourceSpec{Domain: "branch.example.com", AddressTranslation: &translation}
+			endpoints := []*endpoint.Endpoint{
+				endpoint.NewEndpoint("printer.branch.example.com", endpoint.RecordTypeA, "192.168.128.5"),
+				endpoint.NewEndpoint("nas.branch.example.com", endpoint.RecordTypeA, "172.16.0.9"),
+				endpoint.NewEndpoint("www.branch.example.com", endpoint.RecordTypeCNAME, "printer.branch.example.com"),
+				endpoint.NewEndpoint("printer.other.example.com", endpoint.RecordTypeA, "192.168.128.6"),
+			}
+
+			got, err := applyAddressTranslation(spec, endpoints)
+			if err != nil {
+				t.Fatal(err)
+			}
+			equalRecords(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyAddressTranslationInvalid(t *testing.T) {
+	spec := &dnsv1alpha1.MerakiSourceSpec{
+		Domain: "branch.example.com",
+		AddressTranslation: &dnsv1alpha1.AddressTranslation{
+			Mappings: []dnsv1alpha1.AddressMapping{{From: "192.168.128.0/24", To: "10.50.0.0/16"}},
+		},
+	}
+	if _, err := applyAddressTranslation(spec, nil); err == nil {
+		t.Error("expected mismatched prefix sizes to be refused")
+	}
+}
+
+func TestTranslatedEndpoints(t *testing.T) {
+	spec := &dnsv1alpha1.MerakiSourceSpec{
+		Domain: "branch.example.com",
+		AddressTranslation: &dnsv1alpha1.AddressTranslation{
+			Mappings:         []dnsv1alpha1.AddressMapping{{From: "192.168.128.0/24", To: "10.50.128.0/24"}},
+			SeparateEndpoint: true,
+		},
+	}
+	mappings, err := newAddressMappings(spec.AddressTranslation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("printer.branch.example.com", endpoint.RecordTypeA, "192.168.128.5"),
+		endpoint.NewEndpoint("www.branch.example.com", endpoint.RecordTypeCNAME, "printer.branch.example.com"),
+	}
+
+	equalRecords(t, translatedEndpoints(spec, mappings, endpoints), []string{
+		"printer.branch.example.com A 10.50.128.5",
+	})
+	// the source records are left as is
+	equalRecords(t, endpoints, []string{
+		"printer.branch.example.com A 192.168.128.5",
+		"www.branch.example.com CNAME printer.branch.example.com",
+	})
+}
@@ -0,0 +1,80 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// uplink is the template data for uplink records
+type uplink struct {
+	*meraki.Uplink
+	Device *meraki.Device
+}
+
+// uplinkEndpoints returns an A record with the public IP of each appliance
+// uplink, named after the device and interface, e.g.
+// branch1-wan1.example.com
+func uplinkEndpoints(spec *dnsv1alpha1.MerakiSourceSpec, snapshot *Snapshot) ([]*endpoint.Endpoint, error) {
+	ttl := dnsv1alpha1.DefaultUplinkTTL
+	activeOnly := false
+	if spec.Uplinks != nil {
+		if spec.Uplinks.TTL != nil {
+			ttl = *spec.Uplinks.TTL
+		}
+		activeOnly = spec.Uplinks.ActiveOnly
+	}
+
+	var endpoints []*endpoint.Endpoint
+	for _, device := range snapshot.Devices {
+		for _, u := range snapshot.Uplinks[device.Serial] {
+			if u.PublicIP == "" {
+				continue
+			}
+			if activeOnly && u.Status != meraki.UplinkStatusActive {
+				continue
+			}
+
+			data := &uplink{Uplink: u, Device: device}
+			labels, err := endpointLabels(spec, nil, data)
+			if err != nil {
+				return nil, fmt.Errorf("device %s: %v", device.Serial, err)
+			}
+			properties, err := providerSpecific(spec, nil, data)
+			if err != nil {
+				return nil, fmt.Errorf("device %s: %v", device.Serial, err)
+			}
+
+			// device names may contain dots, slashes or underscores
+			name := dnsLabel(device.DNSName()) + "-" + dnsLabel(u.DNSName())
+			e := endpoint.NewEndpointWithTTL(name+"."+spec.Domain, endpoint.RecordTypeA, endpoint.TTL(ttl), u.PublicIP)
+			for k, v := range labels {
+				e.Labels[k] = v
+			}
+			for _, p := range properties {
+				e.WithProviderSpecific(p.Name, p.Value)
+			}
+			endpoints = append(endpoints, e)
+		}
+	}
+
+	return endpoints, nil
+}
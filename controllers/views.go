@@ -0,0 +1,149 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kubernetes-incubator/external-dns/endpoint"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ref "k8s.io/client-go/tools/reference"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
+)
+
+// endpointView is a set of records published to their own DNSEndpoint, e.g.
+// so a different external-dns instance can publish them to another zone
+type endpointView struct {
+	labels    map[string]string
+	endpoints []*endpoint.Endpoint
+}
+
+// viewEndpoint is the DNSEndpoint a view is written to
+type viewEndpoint struct {
+	dnsEndpoint endpoint.DNSEndpoint
+
+	// view is nil if the source no longer has the view, in which case the
+	// DNSEndpoint is deleted
+	view *endpointView
+}
+
+// getViewEndpoint gets the current DNSEndpoint of a view so the records can be
+// compared before they are written
+func (r *MerakiSourceReconciler) getViewEndpoint(ctx context.Context, log logr.Logger, target types.NamespacedName, view *endpointView) (*viewEndpoint, error) {
+	v := &viewEndpoint{view: view}
+	if err := r.Get(ctx, target, &v.dnsEndpoint); err != nil {
+		if !apierrs.IsNotFound(err) {
+			log.Error(err, "unable to get dns endpoint", "dns-endpoint", target)
+			return nil, err
+		}
+		v.dnsEndpoint = endpoint.DNSEndpoint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      target.Name,
+				Namespace: target.Namespace,
+			},
+		}
+	}
+	return v, nil
+}
+
+// deletionBlocked applies the deletion protection of the source to the
+// records of the view. Removing the whole view is a change of the spec and
+// is not blocked
+func (v *viewEndpoint) deletionBlocked(spec *dnsv1alpha1.MerakiSourceSpec) string {
+	if v.view == nil {
+		return ""
+	}
+	msg := deletionBlocked(spec, v.dnsEndpoint.Spec.Endpoints, v.view.endpoints)
+	if msg == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s: %s", v.dnsEndpoint.GetName(), msg)
+}
+
+// viewDryRunStatus compares the records of the view with its current DNSEndpoint.
+// It returns nil if there is neither a view nor a DNSEndpoint
+func (r *MerakiSourceReconciler) viewDryRunStatus(source Source, v *viewEndpoint) *dnsv1alpha1.DryRunStatus {
+	var desired []*endpoint.Endpoint
+	if v.view != nil {
+		desired = v.view.endpoints
+	} else if r.isNew(v.dnsEndpoint) || !metav1.IsControlledBy(&v.dnsEndpoint, source) {
+		return nil
+	}
+	status := dryRunStatus(v.dnsEndpoint.Spec.Endpoints, desired)
+	status.Name = v.dnsEndpoint.GetName()
+	return status
+}
+
+// syncViewEndpoint writes the records of a view to its DNSEndpoint and returns
+// a reference to it. If the source no longer has the view the DNSEndpoint is
+// deleted
+func (r *MerakiSourceReconciler) syncViewEndpoint(ctx context.Context, log logr.Logger, source Source, v *viewEndpoint) (*corev1.ObjectReference, error) {
+	dnsEndpoint := &v.dnsEndpoint
+	target := types.NamespacedName{Namespace: dnsEndpoint.GetNamespace(), Name: dnsEndpoint.GetName()}
+
+	if v.view == nil {
+		if r.isNew(*dnsEndpoint) || !metav1.IsControlledBy(dnsEndpoint, source) {
+			return nil, nil
+		}
+		if err := r.Delete(ctx, dnsEndpoint); err != nil && !apierrs.IsNotFound(err) {
+			log.Error(err, "unable to delete dns endpoint", "dns-endpoint", target)
+			return nil, err
+		}
+		log.V(1).Info("deleted dns endpoint", "dns-endpoint", target)
+		return nil, nil
+	}
+
+	if err := ctrl.SetControllerReference(source, dnsEndpoint, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	labels := dnsEndpoint.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range v.view.labels {
+		labels[k] = v
+	}
+	dnsEndpoint.SetLabels(labels)
+	dnsEndpoint.Spec.Endpoints = v.view.endpoints
+
+	if r.isNew(*dnsEndpoint) {
+		if err := r.Create(ctx, dnsEndpoint); err != nil {
+			log.Error(err, "failed to create dns endpoint", "dns-endpoint", target)
+			return nil, err
+		}
+		log.V(1).Info("created dns endpoint", "dns-endpoint", target)
+	} else {
+		if err := r.Update(ctx, dnsEndpoint); err != nil {
+			log.Error(err, "failed to update dns endpoint", "dns-endpoint", target)
+			return nil, err
+		}
+		log.V(1).Info("updated dns endpoint", "dns-endpoint", target)
+	}
+
+	ref, err := ref.GetReference(r.Scheme, dnsEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to make reference to dns endpoint: %v", err)
+	}
+	return ref, nil
+}
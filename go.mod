@@ -25,6 +25,7 @@ require (
 	golang.org/x/mod v0.3.0 // indirect
 	golang.org/x/net v0.0.0-20200625001655-4c5254603344 // indirect
 	golang.org/x/text v0.3.3 // indirect
+	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
 	golang.org/x/tools v0.0.0-20200624225443-88f3c62a19ff // indirect
 	k8s.io/api v0.17.0
 	k8s.io/apimachinery v0.17.0
@@ -16,15 +16,17 @@ limitations under the License.
 package main
 
 import (
-	"errors"
 	"flag"
+	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"time"
 
 	"github.com/kubernetes-incubator/external-dns/endpoint"
 	dnsv1alpha1 "github.com/ryane/meraki-external-dns-source/api/v1alpha1"
 	"github.com/ryane/meraki-external-dns-source/controllers"
+	"github.com/ryane/meraki-external-dns-source/pkg/meraki"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -59,15 +61,35 @@ func main() {
 	var enableLeaderElection bool
 	var throttleInterval time.Duration
 	var requeueInterval time.Duration
+	var policyCacheTTL time.Duration
 	var apiKey string
 	var apiKeyFile string
+	var txtPrefix string
+	var dryRun bool
+	var namespace string
+	var region string
+	var proxyURL string
+	var proxyCredentialsFile string
+	var caFile string
+	var clientCertFile string
+	var clientKeyFile string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	flag.DurationVar(&throttleInterval, "throttle-interval", 1*time.Minute, "Attempt to restrict Meraki API calls to only occur once within this interval. There are conditions where this does not apply.")
 	flag.DurationVar(&requeueInterval, "requeue-interval", 5*time.Minute, "How long to wait before requeueing Meraki Sources.")
+	flag.DurationVar(&policyCacheTTL, "policy-cache-ttl", controllers.DefaultPolicyCacheTTL, "How long client policies are cached for group policy filters. Each client policy takes one Meraki API call to look up.")
 	flag.StringVar(&apiKey, "api-key", "", "The API key for the Meraki API.")
 	flag.StringVar(&apiKeyFile, "api-key-file", "", "Reads the API key from this file.")
+	flag.StringVar(&txtPrefix, "txt-prefix", "", "The TXT registry prefix used by external-dns (--txt-prefix). Metadata records require it to be set and are not allowed to use the same prefix.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Compute the records for every MerakiSource and report them in the status without writing DNSEndpoints.")
+	flag.StringVar(&namespace, "namespace", os.Getenv("POD_NAMESPACE"), "The namespace the controller runs in. ClusterMerakiSource credentials are read from Secrets in this namespace.")
+	flag.StringVar(&region, "region", meraki.DefaultRegion, "The default Meraki dashboard region: global, china, india, canada or fedramp.")
+	flag.StringVar(&proxyURL, "proxy-url", "", "The URL of an HTTP proxy for Meraki API requests. Defaults to the HTTPS_PROXY and NO_PROXY environment variables.")
+	flag.StringVar(&proxyCredentialsFile, "proxy-credentials-file", "", "Reads proxy credentials in the form username:password from this file.")
+	flag.StringVar(&caFile, "ca-file", "", "A PEM bundle of additional CA certificates to trust for Meraki API requests.")
+	flag.StringVar(&clientCertFile, "client-cert-file", "", "A PEM client certificate to present for Meraki API requests.")
+	flag.StringVar(&clientKeyFile, "client-key-file", "", "The PEM private key of the client certificate.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(func(o *zap.Options) {
@@ -94,7 +116,17 @@ func main() {
 	}
 
 	if apiKey == "" {
-		setupLog.Error(errors.New("A Meraki API key is required"), "unable to start manager")
+		setupLog.Info("no Meraki API key provided, sources must reference a MerakiConnection")
+	}
+
+	if _, err := meraki.RegionBaseURL(region); err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	apiOptions, err := merakiOptions(proxyURL, proxyCredentialsFile, caFile, clientCertFile, clientKeyFile)
+	if err != nil {
+		setupLog.Error(err, "unable to configure Meraki API client")
 		os.Exit(1)
 	}
 
@@ -109,17 +141,44 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.MerakiSourceReconciler{
+	sourceReconciler := &controllers.MerakiSourceReconciler{
 		Client:              mgr.GetClient(),
 		Log:                 ctrl.Log.WithName("controllers").WithName("MerakiSource"),
 		Scheme:              mgr.GetScheme(),
+		APIReader:           mgr.GetAPIReader(),
+		Namespace:           namespace,
 		APIKey:              apiKey,
+		Region:              region,
+		APIOptions:          apiOptions,
 		APIThrottleInterval: throttleInterval,
 		RequeueInterval:     requeueInterval,
-	}).SetupWithManager(mgr); err != nil {
+		TXTPrefix:           txtPrefix,
+		DryRun:              dryRun,
+		PolicyCacheTTL:      policyCacheTTL,
+	}
+	if err = sourceReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MerakiSource")
 		os.Exit(1)
 	}
+	if err = (&controllers.ClusterMerakiSourceReconciler{
+		MerakiSourceReconciler: sourceReconciler,
+		Log:                    ctrl.Log.WithName("controllers").WithName("ClusterMerakiSource"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterMerakiSource")
+		os.Exit(1)
+	}
+	if err = (&controllers.MerakiConnectionReconciler{
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("MerakiConnection"),
+		Scheme:        mgr.GetScheme(),
+		APIReader:     mgr.GetAPIReader(),
+		Region:        region,
+		APIOptions:    apiOptions,
+		CheckInterval: requeueInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MerakiConnection")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -128,3 +187,39 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// merakiOptions returns the Meraki API client options for the proxy and TLS
+// flags
+func merakiOptions(proxyURL, proxyCredentialsFile, caFile, clientCertFile, clientKeyFile string) ([]func(*meraki.Api), error) {
+	var opts []func(*meraki.Api)
+
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %v", err)
+		}
+		opts = append(opts, meraki.Proxy(u))
+	}
+
+	if proxyCredentialsFile != "" {
+		buf, err := ioutil.ReadFile(proxyCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read proxy credentials: %v", err)
+		}
+		credentials, err := meraki.ProxyCredentials(string(buf))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, credentials)
+	}
+
+	if caFile != "" || clientCertFile != "" || clientKeyFile != "" {
+		config, err := meraki.LoadTLSConfig(caFile, clientCertFile, clientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, meraki.TLSConfig(config))
+	}
+
+	return opts, nil
+}
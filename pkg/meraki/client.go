@@ -1,18 +1,73 @@
 package meraki
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 )
 
 type Api struct {
-	apiKey  string
-	baseURL string
+	apiKey     string
+	baseURL    string
+	proxyURL   *url.URL
+	proxyUser  *url.Userinfo
+	tlsConfig  *tls.Config
+	limiter    *rate.Limiter
+	httpClient *http.Client
+}
+
+// transportKey identifies the connection settings of a client. Clients with
+// the same settings share a transport, and with it a pool of idle connections.
+// Proxy credentials are sent per request and are not part of the key, so
+// rotating them doesn't leave transports behind
+type transportKey struct {
+	proxyURL  string
+	tlsConfig *tls.Config
+}
+
+var transports = struct {
+	sync.Mutex
+	m map[transportKey]*http.Transport
+}{m: map[transportKey]*http.Transport{}}
+
+// proxyUserKey is the request context key of the proxy credentials
+type proxyUserKey struct{}
+
+// transport returns the shared transport for the connection settings of the
+// client, creating it on first use
+func (c *Api) transport() *http.Transport {
+	key := transportKey{tlsConfig: c.tlsConfig}
+	if c.proxyURL != nil {
+		key.proxyURL = c.proxyURL.String()
+	}
+
+	transports.Lock()
+	defer transports.Unlock()
+	if t, ok := transports.m[key]; ok {
+		return t
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if c.proxyURL != nil {
+		proxy = http.ProxyURL(c.proxyURL)
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = withProxyUser(proxy)
+	if c.tlsConfig != nil {
+		t.TLSClientConfig = c.tlsConfig
+	}
+	transports.m[key] = t
+	return t
 }
 
 func New(apiKey string, opts ...func(*Api)) *Api {
@@ -23,6 +78,22 @@ func New(apiKey string, opts ...func(*Api)) *Api {
 	for _, option := range opts {
 		option(&m)
 	}
+
+	// credentials in the proxy URL are sent per request like the ones set
+	// with ProxyCredentials, which take precedence
+	if m.proxyURL != nil && m.proxyURL.User != nil {
+		if m.proxyUser == nil {
+			m.proxyUser = m.proxyURL.User
+		}
+		proxyURL := *m.proxyURL
+		proxyURL.User = nil
+		m.proxyURL = &proxyURL
+	}
+
+	m.httpClient = &http.Client{
+		Transport:     m.transport(),
+		CheckRedirect: m.checkRedirect,
+	}
 	return &m
 }
 
@@ -32,6 +103,62 @@ func BaseURL(url string) func(*Api) {
 	}
 }
 
+// Proxy sends requests through the proxy at proxyURL instead of the proxy
+// configured in the environment
+func Proxy(proxyURL *url.URL) func(*Api) {
+	return func(c *Api) {
+		c.proxyURL = proxyURL
+	}
+}
+
+// ProxyCredentials authenticates to the proxy with credentials in the form
+// username:password. It applies to the proxy from the environment as well as
+// one set with Proxy
+func ProxyCredentials(credentials string) (func(*Api), error) {
+	parts := strings.SplitN(strings.TrimSpace(credentials), ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, errors.New("proxy credentials must be in the form username:password")
+	}
+	user := url.UserPassword(parts[0], parts[1])
+	return func(c *Api) {
+		c.proxyUser = user
+	}, nil
+}
+
+// TLSConfig sets the TLS configuration for requests, e.g. to trust the CA of
+// a TLS inspecting proxy or to present a client certificate
+func TLSConfig(config *tls.Config) func(*Api) {
+	return func(c *Api) {
+		c.tlsConfig = config
+	}
+}
+
+// RateLimiter limits the rate of requests. The limiter can be shared by
+// clients for the same organization to stay within the Meraki API limits
+func RateLimiter(limiter *rate.Limiter) func(*Api) {
+	return func(c *Api) {
+		c.limiter = limiter
+	}
+}
+
+// withProxyUser adds the credentials in the request context to the URLs
+// returned by a proxy function
+func withProxyUser(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := proxy(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		user, ok := req.Context().Value(proxyUserKey{}).(*url.Userinfo)
+		if !ok {
+			return proxyURL, nil
+		}
+		authenticated := *proxyURL
+		authenticated.User = user
+		return &authenticated, nil
+	}
+}
+
 func (c *Api) FindOrganization(name string) (*Organization, error) {
 	orgs, err := c.Organizations()
 	if err != nil {
@@ -82,6 +209,22 @@ func (c *Api) Organizations() ([]*Organization, error) {
 	return orgs, err
 }
 
+// Organization returns the organization with the given ID
+func (c *Api) Organization(organizationID string) (*Organization, error) {
+	var org Organization
+	resp, err := c.get(fmt.Sprintf("organizations/%s", organizationID))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &org)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &org, nil
+}
+
 func (c *Api) Networks(organizationID string) ([]*Network, error) {
 	var networks []*Network
 	resp, err := c.get(fmt.Sprintf("organizations/%s/networks", organizationID))
@@ -97,6 +240,22 @@ func (c *Api) Networks(organizationID string) ([]*Network, error) {
 	return networks, nil
 }
 
+// Network returns the network with the given ID
+func (c *Api) Network(networkID string) (*Network, error) {
+	var network Network
+	resp, err := c.get(fmt.Sprintf("networks/%s", networkID))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &network)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &network, nil
+}
+
 func (c *Api) Clients(networkID string) ([]*Client, error) {
 	var clients []*Client
 	resp, err := c.get(fmt.Sprintf("networks/%s/clients", networkID))
@@ -112,6 +271,125 @@ func (c *Api) Clients(networkID string) ([]*Client, error) {
 	return clients, nil
 }
 
+func (c *Api) Devices(networkID string) ([]*Device, error) {
+	var devices []*Device
+	resp, err := c.get(fmt.Sprintf("networks/%s/devices", networkID))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &devices)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return devices, nil
+}
+
+// DeviceUplinks returns the status of the uplinks of a device, including the
+// public IP of each appliance WAN interface
+func (c *Api) DeviceUplinks(networkID, serial string) ([]*Uplink, error) {
+	var uplinks []*Uplink
+	resp, err := c.get(fmt.Sprintf("networks/%s/devices/%s/uplink", networkID, serial))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &uplinks)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uplinks, nil
+}
+
+// OneToOneNatRules returns the 1:1 NAT rules of the appliance in a network
+func (c *Api) OneToOneNatRules(networkID string) ([]*OneToOneNatRule, error) {
+	var rules struct {
+		Rules []*OneToOneNatRule `json:"rules"`
+	}
+	resp, err := c.get(fmt.Sprintf("networks/%s/oneToOneNatRules", networkID))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rules.Rules, nil
+}
+
+// PortForwardingRules returns the port forwarding rules of the appliance in a
+// network
+func (c *Api) PortForwardingRules(networkID string) ([]*PortForwardingRule, error) {
+	var rules struct {
+		Rules []*PortForwardingRule `json:"rules"`
+	}
+	resp, err := c.get(fmt.Sprintf("networks/%s/portForwardingRules", networkID))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rules.Rules, nil
+}
+
+// ClientPolicy returns the policy applied to a client
+func (c *Api) ClientPolicy(networkID, clientID string) (*ClientPolicy, error) {
+	var policy ClientPolicy
+	resp, err := c.get(fmt.Sprintf("networks/%s/clients/%s/policy", networkID, clientID))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &policy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &policy, nil
+}
+
+// GroupPolicies returns the group policies defined in a network
+func (c *Api) GroupPolicies(networkID string) ([]*GroupPolicy, error) {
+	var policies []*GroupPolicy
+	resp, err := c.get(fmt.Sprintf("networks/%s/groupPolicies", networkID))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &policies)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+// SMDevices returns the devices enrolled in Systems Manager in a network
+func (c *Api) SMDevices(networkID string) ([]*SMDevice, error) {
+	var devices struct {
+		Devices []*SMDevice `json:"devices"`
+	}
+	resp, err := c.get(fmt.Sprintf("networks/%s/sm/devices", networkID))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		err = json.Unmarshal(resp, &devices)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return devices.Devices, nil
+}
+
 func (c *Api) OnlineClients(networkID string) ([]*Client, error) {
 	var clients []*Client
 	allClients, err := c.Clients(networkID)
@@ -127,14 +405,22 @@ func (c *Api) OnlineClients(networkID string) ([]*Client, error) {
 }
 
 func (c *Api) get(path string) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
 	url := c.baseURL + path + "?perPage=1000"
-	httpClient := &http.Client{}
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Add("X-Cisco-Meraki-API-Key", c.apiKey)
-	resp, err := httpClient.Do(req)
+	if c.proxyUser != nil {
+		req = req.WithContext(context.WithValue(req.Context(), proxyUserKey{}, c.proxyUser))
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
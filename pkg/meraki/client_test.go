@@ -0,0 +1,84 @@
+package meraki
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestTransportIsShared(t *testing.T) {
+	proxyURL, err := url.Parse("http://proxy.example.com:3128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig := &tls.Config{}
+
+	a := New("a", Proxy(proxyURL), TLSConfig(tlsConfig))
+	b := New("b", Proxy(proxyURL), TLSConfig(tlsConfig))
+	if a.httpClient.Transport != b.httpClient.Transport {
+		t.Error("expected clients with the same settings to share a transport")
+	}
+
+	if New("c").httpClient.Transport == a.httpClient.Transport {
+		t.Error("expected clients with a different proxy to use their own transport")
+	}
+	if New("d", Proxy(proxyURL)).httpClient.Transport == a.httpClient.Transport {
+		t.Error("expected clients with a different TLS config to use their own transport")
+	}
+
+	credentials, err := ProxyCredentials("user:pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if New("e", Proxy(proxyURL), TLSConfig(tlsConfig), credentials).httpClient.Transport != a.httpClient.Transport {
+		t.Error("expected clients with proxy credentials to share the transport")
+	}
+}
+
+func TestProxyCredentialsPerRequest(t *testing.T) {
+	var auth []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Proxy-Authorization"))
+		w.Write([]byte("[]"))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withUser := *proxyURL
+	withUser.User = url.UserPassword("url-user", "url-pass")
+	rotated, err := ProxyCredentials("user:rotated")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseURL := BaseURL("http://api.example.com/api/v0/")
+	clients := []*Api{
+		New("a", baseURL, Proxy(&withUser)),
+		New("b", baseURL, Proxy(proxyURL), rotated),
+		New("c", baseURL, Proxy(proxyURL)),
+	}
+	for _, c := range clients {
+		if c.httpClient.Transport != clients[0].httpClient.Transport {
+			t.Error("expected clients with different proxy credentials to share a transport")
+		}
+		if _, err := c.Organizations(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{basicAuth("url-user", "url-pass"), basicAuth("user", "rotated"), ""}
+	if !reflect.DeepEqual(auth, want) {
+		t.Errorf("got proxy authorization %q, want %q", auth, want)
+	}
+}
+
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
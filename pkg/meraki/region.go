@@ -0,0 +1,70 @@
+package meraki
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultRegion is the region of the global Meraki dashboard
+const DefaultRegion = "global"
+
+// regionHosts maps region names to the dashboard API host of the region
+var regionHosts = map[string]string{
+	"global":  "api.meraki.com",
+	"china":   "api.meraki.cn",
+	"india":   "api.meraki.in",
+	"canada":  "api.meraki.ca",
+	"fedramp": "api.gov-meraki.com",
+}
+
+// RegionBaseURL returns the API base URL of a Meraki dashboard region
+func RegionBaseURL(region string) (string, error) {
+	if region == "" {
+		region = DefaultRegion
+	}
+	host, ok := regionHosts[strings.ToLower(region)]
+	if !ok {
+		return "", fmt.Errorf("unknown Meraki region %q", region)
+	}
+	return fmt.Sprintf("https://%s/api/v0/", host), nil
+}
+
+// Region sends requests to the dashboard API of a region. It returns an error
+// for unknown regions
+func Region(region string) (func(*Api), error) {
+	baseURL, err := RegionBaseURL(region)
+	if err != nil {
+		return nil, err
+	}
+	return BaseURL(baseURL), nil
+}
+
+// checkRedirect follows the redirects the Meraki API issues for organizations
+// hosted on another shard, e.g. from api.meraki.com to n123.meraki.com. The
+// API key header is copied to redirected requests, so redirects are only
+// followed with the scheme of the base URL to hosts within its domain
+func (c *Api) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return err
+	}
+
+	if req.URL.Scheme != base.Scheme {
+		return fmt.Errorf("refusing redirect from %s to %s", base.Scheme, req.URL.Scheme)
+	}
+
+	host := req.URL.Hostname()
+	domain := strings.TrimPrefix(base.Hostname(), "api.")
+	if host != base.Hostname() && host != domain && !strings.HasSuffix(host, "."+domain) {
+		return fmt.Errorf("refusing redirect to %s outside of %s", host, domain)
+	}
+
+	return nil
+}
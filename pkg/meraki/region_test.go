@@ -0,0 +1,50 @@
+package meraki
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCheckRedirect(t *testing.T) {
+	c := New("key")
+
+	tests := []struct {
+		name    string
+		url     string
+		allowed bool
+	}{
+		{"same host", "https://api.meraki.com/api/v0/organizations", true},
+		{"shard on the same domain", "https://n123.meraki.com/api/v0/organizations", true},
+		{"downgrade to http on the same host", "http://api.meraki.com/api/v0/organizations", false},
+		{"downgrade to http on a shard", "http://n123.meraki.com/api/v0/organizations", false},
+		{"foreign host", "https://example.com/api/v0/organizations", false},
+		{"foreign host with the domain as a prefix", "https://meraki.com.example.com/api/v0/organizations", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.checkRedirect(req, nil)
+			if tt.allowed && err != nil {
+				t.Errorf("expected redirect to %s to be allowed, got %v", tt.url, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Errorf("expected redirect to %s to be refused", tt.url)
+			}
+		})
+	}
+}
+
+func TestCheckRedirectLimit(t *testing.T) {
+	c := New("key")
+	req, err := http.NewRequest("GET", "https://api.meraki.com/api/v0/organizations", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.checkRedirect(req, make([]*http.Request, 10)); err == nil {
+		t.Error("expected the 11th redirect to be refused")
+	}
+}
@@ -1,6 +1,7 @@
 package meraki
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 )
@@ -12,15 +13,34 @@ type Organization struct {
 }
 
 type Network struct {
-	ID                      string      `json:"id"`
-	OrganizationID          string      `json:"organizationId"`
-	Name                    string      `json:"name"`
-	TimeZone                string      `json:"timeZone"`
-	Tags                    interface{} `json:"tags"`
-	ProductTypes            []string    `json:"productTypes"`
-	Type                    string      `json:"type"`
-	DisableMyMerakiCom      bool        `json:"disableMyMerakiCom"`
-	DisableRemoteStatusPage bool        `json:"disableRemoteStatusPage"`
+	ID                      string   `json:"id"`
+	OrganizationID          string   `json:"organizationId"`
+	Name                    string   `json:"name"`
+	TimeZone                string   `json:"timeZone"`
+	Tags                    Tags     `json:"tags"`
+	Notes                   string   `json:"notes"`
+	ProductTypes            []string `json:"productTypes"`
+	Type                    string   `json:"type"`
+	DisableMyMerakiCom      bool     `json:"disableMyMerakiCom"`
+	DisableRemoteStatusPage bool     `json:"disableRemoteStatusPage"`
+}
+
+// Tags are the tags of a network. The v0 API returns them as a space
+// separated string and v1 as an array
+type Tags []string
+
+func (t *Tags) UnmarshalJSON(data []byte) error {
+	var tags []string
+	if err := json.Unmarshal(data, &tags); err == nil {
+		*t = tags
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*t = strings.Fields(s)
+	return nil
 }
 
 type Client struct {
@@ -28,8 +48,8 @@ type Client struct {
 	Mac                string      `json:"mac"`
 	Description        string      `json:"description"`
 	IP                 string      `json:"ip"`
-	IP6                interface{} `json:"ip6"`
-	User               interface{} `json:"user"`
+	IP6                string      `json:"ip6"`
+	User               string      `json:"user"`
 	FirstSeen          time.Time   `json:"firstSeen"`
 	LastSeen           time.Time   `json:"lastSeen"`
 	Manufacturer       string      `json:"manufacturer"`
@@ -37,7 +57,7 @@ type Client struct {
 	RecentDeviceSerial string      `json:"recentDeviceSerial"`
 	RecentDeviceName   string      `json:"recentDeviceName"`
 	RecentDeviceMac    string      `json:"recentDeviceMac"`
-	Ssid               interface{} `json:"ssid"`
+	Ssid               string      `json:"ssid"`
 	Vlan               int         `json:"vlan"`
 	Switchport         interface{} `json:"switchport"`
 	Usage              struct {
@@ -56,3 +76,102 @@ func (c *Client) DNSName() string {
 	name = strings.Replace(name, ":", "-", -1)
 	return name
 }
+
+type Device struct {
+	Name      string  `json:"name"`
+	Serial    string  `json:"serial"`
+	Mac       string  `json:"mac"`
+	Model     string  `json:"model"`
+	NetworkID string  `json:"networkId"`
+	LanIP     string  `json:"lanIp"`
+	Address   string  `json:"address"`
+	Lat       float64 `json:"lat"`
+	Lng       float64 `json:"lng"`
+	Firmware  string  `json:"firmware"`
+}
+
+// IsAppliance returns true for MX, vMX and Z series security appliances
+func (d *Device) IsAppliance() bool {
+	for _, prefix := range []string{"MX", "vMX", "Z"} {
+		if strings.HasPrefix(d.Model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Device) DNSName() string {
+	name := strings.ToLower(d.Name)
+	if name == "" {
+		name = strings.ToLower(d.Serial)
+	}
+	name = strings.Replace(name, " ", "-", -1)
+	return name
+}
+
+type Uplink struct {
+	Interface     string `json:"interface"`
+	Status        string `json:"status"`
+	IP            string `json:"ip"`
+	Gateway       string `json:"gateway"`
+	PublicIP      string `json:"publicIp"`
+	DNS           string `json:"dns"`
+	UsingStaticIP bool   `json:"usingStaticIp"`
+}
+
+// UplinkStatusActive is the status of an uplink that is carrying traffic
+const UplinkStatusActive = "Active"
+
+// DNSName returns the interface name as a DNS label, e.g. wan1 for WAN 1
+func (u *Uplink) DNSName() string {
+	return strings.Replace(strings.ToLower(u.Interface), " ", "", -1)
+}
+
+type OneToOneNatRule struct {
+	Name           string `json:"name"`
+	LanIP          string `json:"lanIp"`
+	PublicIP       string `json:"publicIp"`
+	Uplink         string `json:"uplink"`
+	AllowedInbound []struct {
+		Protocol         string   `json:"protocol"`
+		DestinationPorts []string `json:"destinationPorts"`
+		AllowedIPs       []string `json:"allowedIps"`
+	} `json:"allowedInbound"`
+}
+
+type PortForwardingRule struct {
+	Name       string   `json:"name"`
+	LanIP      string   `json:"lanIp"`
+	Protocol   string   `json:"protocol"`
+	PublicPort string   `json:"publicPort"`
+	LocalPort  string   `json:"localPort"`
+	Uplink     string   `json:"uplink"`
+	AllowedIPs []string `json:"allowedIps"`
+}
+
+// ClientPolicy is the policy applied to a client. Type is Normal, Whitelisted,
+// Blocked or Group policy, in which case GroupPolicyID is set
+type ClientPolicy struct {
+	Mac           string      `json:"mac"`
+	Type          string      `json:"type"`
+	GroupPolicyID json.Number `json:"groupPolicyId"`
+}
+
+// ClientPolicyTypeGroupPolicy is the type of a client policy that applies a
+// group policy
+const ClientPolicyTypeGroupPolicy = "Group policy"
+
+type GroupPolicy struct {
+	GroupPolicyID json.Number `json:"groupPolicyId"`
+	Name          string      `json:"name"`
+}
+
+// SMDevice is a device enrolled in Systems Manager
+type SMDevice struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	WifiMac      string `json:"wifiMac"`
+	SerialNumber string `json:"serialNumber"`
+	OsName       string `json:"osName"`
+	SystemModel  string `json:"systemModel"`
+}
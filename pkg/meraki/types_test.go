@@ -0,0 +1,54 @@
+package meraki
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTagsUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Tags
+		wantErr bool
+	}{
+		{
+			name: "v0 space separated string",
+			data: `{"tags": " dns-domain=office.example.com  branch "}`,
+			want: Tags{"dns-domain=office.example.com", "branch"},
+		},
+		{
+			name: "v1 array",
+			data: `{"tags": ["dns-domain=office.example.com", "branch"]}`,
+			want: Tags{"dns-domain=office.example.com", "branch"},
+		},
+		{
+			name: "empty string",
+			data: `{"tags": ""}`,
+			want: Tags{},
+		},
+		{
+			name: "missing",
+			data: `{}`,
+		},
+		{
+			name:    "invalid",
+			data:    `{"tags": 1}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var network Network
+			err := json.Unmarshal([]byte(tt.data), &network)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(network.Tags, tt.want) {
+				t.Errorf("got tags %q, want %q", network.Tags, tt.want)
+			}
+		})
+	}
+}